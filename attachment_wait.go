@@ -0,0 +1,173 @@
+package maxbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+// ErrAttachmentNotReady означает, что сервер ещё обрабатывает загруженный файл.
+var ErrAttachmentNotReady = errors.New("attachment is not ready")
+
+const (
+	defaultAttachmentWaitTimeout  = 5 * time.Minute
+	defaultAttachmentWaitPause    = time.Second
+	defaultAttachmentWaitMaxPause = 30 * time.Second
+)
+
+// AttachmentWaitConfig настройки ожидания обработки вложения. Нулевые значения заменяются значениями по умолчанию.
+type AttachmentWaitConfig struct {
+	// Timeout общее время ожидания.
+	Timeout time.Duration
+	// Pause пауза перед первой повторной проверкой. Пауза удваивается с каждой проверкой.
+	Pause time.Duration
+	// MaxPause максимальная пауза между проверками.
+	MaxPause time.Duration
+	// OnStatus вызывается после каждой проверки.
+	OnStatus func(AttachmentStatus)
+}
+
+// AttachmentStatus результат проверки готовности вложения.
+type AttachmentStatus struct {
+	// Attempt номер проверки, начиная с 1.
+	Attempt int
+	// Elapsed время с начала ожидания.
+	Elapsed time.Duration
+	// Ready вложение обработано.
+	Ready bool
+	// Err ошибка проверки. ErrAttachmentNotReady или ошибка API attachment.not.ready означают, что обработка продолжается.
+	Err error
+}
+
+// WithAttachmentWait задаёт, сколько Send повторяет отправку сообщения, пока вложение обрабатывается
+// (ошибка attachment.not.ready). По умолчанию выполняется 3 попытки с паузой 1 и 2 секунды,
+// чего не хватает для длинных видео. Сетевые ошибки и таймауты по-прежнему повторяются не больше 3 раз.
+func WithAttachmentWait(cfg AttachmentWaitConfig) Opt {
+	return func(c *client) error {
+		c.attachmentWait = newAttachmentWait(cfg)
+
+		return nil
+	}
+}
+
+// WaitAttachmentReady опрашивает GetVideoAttachmentDetails, пока у видео не появятся ссылки на воспроизведение,
+// и возвращает данные готового видео. Для аудио отдельной проверки нет: используйте WithAttachmentWait.
+func (m *Messages) WaitAttachmentReady(ctx context.Context, videoToken string, cfg AttachmentWaitConfig) (res model.VideoAttachmentDetails, err error) {
+	err = newAttachmentWait(cfg).poll(ctx, func() error {
+		details, dErr := m.GetVideoAttachmentDetails(ctx, videoToken)
+		if dErr != nil {
+			return dErr
+		}
+		if details.Urls == nil {
+			return ErrAttachmentNotReady
+		}
+		res = details
+
+		return nil
+	}, isAttachmentNotReady, nil)
+	if err != nil {
+		err = fmt.Errorf("wait video %s: %w", videoToken, err)
+	}
+
+	return
+}
+
+type attachmentWait struct {
+	timeout  time.Duration
+	pause    time.Duration
+	maxPause time.Duration
+	// attempts ограничивает количество проверок, 0 - без ограничения
+	attempts int
+	onStatus func(AttachmentStatus)
+}
+
+func newAttachmentWait(cfg AttachmentWaitConfig) *attachmentWait {
+	w := &attachmentWait{
+		timeout:  cfg.Timeout,
+		pause:    cfg.Pause,
+		maxPause: cfg.MaxPause,
+		onStatus: cfg.OnStatus,
+	}
+	if w.timeout <= 0 {
+		w.timeout = defaultAttachmentWaitTimeout
+	}
+	if w.pause <= 0 {
+		w.pause = defaultAttachmentWaitPause
+	}
+	if w.maxPause <= 0 {
+		w.maxPause = defaultAttachmentWaitMaxPause
+	}
+
+	return w
+}
+
+// defaultAttachmentWait повторяет отправку maxRetries раз, как до появления WithAttachmentWait.
+func defaultAttachmentWait() *attachmentWait {
+	return &attachmentWait{
+		pause:    defaultAttachmentWaitPause,
+		attempts: maxRetries,
+	}
+}
+
+// poll вызывает check, пока он возвращает ошибку, для которой retryable возвращает true.
+// onRetry вызывается перед каждой паузой.
+func (w *attachmentWait) poll(ctx context.Context, check func() error, retryable func(error) bool, onRetry func(attempt int, err error)) error {
+	start := time.Now()
+	pause := w.pause
+
+	for attempt := 1; ; attempt++ {
+		err := check()
+		if w.onStatus != nil {
+			w.onStatus(AttachmentStatus{
+				Attempt: attempt,
+				Elapsed: time.Since(start),
+				Ready:   err == nil,
+				Err:     err,
+			})
+		}
+
+		if err == nil || !retryable(err) {
+			return err
+		}
+		if w.attempts > 0 && attempt >= w.attempts {
+			return err
+		}
+
+		wait := pause
+		if w.timeout > 0 {
+			remaining := w.timeout - time.Since(start)
+			if remaining <= 0 {
+				return fmt.Errorf("not ready after %s: %w", w.timeout, err)
+			}
+			wait = min(wait, remaining)
+		}
+
+		if onRetry != nil {
+			onRetry(attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+
+		pause *= 2
+		if w.maxPause > 0 {
+			pause = min(pause, w.maxPause)
+		}
+	}
+}
+
+func isAttachmentNotReady(err error) bool {
+	if errors.Is(err, ErrAttachmentNotReady) {
+		return true
+	}
+
+	apiErr := &Error{}
+
+	return errors.As(err, &apiErr) && apiErr.IsAttachmentNotReady()
+}
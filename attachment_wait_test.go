@@ -0,0 +1,133 @@
+package maxbot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitAttachmentReady(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		assert.Equal(t, "/videos/video_token", r.URL.Path)
+		if calls < 3 {
+			_, _ = w.Write([]byte(`{"token":"video_token"}`))
+
+			return
+		}
+		_, _ = w.Write([]byte(`{"token":"video_token","urls":{"mp4_720":"https://example.com/720.mp4"},"duration":600}`))
+	}))
+	defer srv.Close()
+
+	api, err := NewApi(testToken, WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	var statuses []AttachmentStatus
+	details, err := api.ExtendedMessages().WaitAttachmentReady(context.Background(), "video_token", AttachmentWaitConfig{
+		Pause:    time.Millisecond,
+		OnStatus: func(s AttachmentStatus) { statuses = append(statuses, s) },
+	})
+	require.NoError(t, err)
+	require.NotNil(t, details.Urls)
+	assert.Equal(t, 600, details.Duration)
+
+	require.Len(t, statuses, 3)
+	assert.ErrorIs(t, statuses[0].Err, ErrAttachmentNotReady)
+	assert.False(t, statuses[1].Ready)
+	assert.True(t, statuses[2].Ready)
+	assert.Equal(t, 3, statuses[2].Attempt)
+}
+
+func TestWaitAttachmentReady_Timeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"token":"video_token"}`))
+	}))
+	defer srv.Close()
+
+	api, err := NewApi(testToken, WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	_, err = api.ExtendedMessages().WaitAttachmentReady(context.Background(), "video_token", AttachmentWaitConfig{
+		Timeout: 20 * time.Millisecond,
+		Pause:   5 * time.Millisecond,
+	})
+	assert.ErrorIs(t, err, ErrAttachmentNotReady)
+	assert.ErrorContains(t, err, "not ready after")
+}
+
+func TestWithAttachmentWait_Send(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls < 6 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":"attachment.not.ready","message":"not ready"}`))
+
+			return
+		}
+		_, _ = w.Write([]byte(`{"message":{"body":{"mid":"mid.1"}}}`))
+	}))
+	defer srv.Close()
+
+	api, err := NewApi(testToken, WithBaseURL(srv.URL), WithAttachmentWait(AttachmentWaitConfig{
+		Pause:    time.Millisecond,
+		MaxPause: 2 * time.Millisecond,
+	}))
+	require.NoError(t, err)
+
+	res, err := api.Messages.Send(context.Background(), NewMessage().SetChat(1).SetText("video"))
+	require.NoError(t, err)
+	assert.Equal(t, "mid.1", res.Message.Body.Mid)
+	assert.Equal(t, 6, calls)
+}
+
+func TestRawWithRetry_DefaultAttempts(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadRequest)
+		_, _ = w.Write([]byte(`{"code":"attachment.not.ready","message":"not ready"}`))
+	}))
+	defer srv.Close()
+
+	api, err := NewApi(testToken, WithBaseURL(srv.URL))
+	require.NoError(t, err)
+	api.client.attachmentWait = defaultAttachmentWait()
+	api.client.attachmentWait.pause = time.Millisecond
+
+	_, err = api.Messages.Send(context.Background(), NewMessage().SetChat(1).SetText("video"))
+	apiErr := &Error{}
+	require.True(t, errors.As(err, &apiErr))
+	assert.True(t, apiErr.IsAttachmentNotReady())
+	assert.Equal(t, maxRetries, calls)
+}
+
+type failingHTTPClient struct {
+	calls int
+}
+
+func (c *failingHTTPClient) Do(*http.Request) (*http.Response, error) {
+	c.calls++
+
+	return nil, errors.New("connection reset")
+}
+
+func TestWithAttachmentWait_NetworkErrorAttempts(t *testing.T) {
+	httpClient := &failingHTTPClient{}
+	api, err := NewApi(testToken, WithHTTPClient(httpClient), WithAttachmentWait(AttachmentWaitConfig{
+		Pause: time.Millisecond,
+	}))
+	require.NoError(t, err)
+
+	_, err = api.Messages.Send(context.Background(), NewMessage().SetChat(1).SetText("text"))
+	var networkErr *NetworkError
+	assert.ErrorAs(t, err, &networkErr)
+	assert.Equal(t, maxRetries, httpClient.calls)
+}
@@ -53,6 +53,25 @@ type MessagesAPI interface {
 	GetVideoAttachmentDetails(ctx context.Context, videoToken string) (model.VideoAttachmentDetails, error)
 }
 
+// ExtendedMessagesAPI MessagesAPI с дополнительными методами отправки и ответа на нажатия кнопок.
+type ExtendedMessagesAPI interface {
+	MessagesAPI
+	CallbackAnswerer
+	SendLong(ctx context.Context, msg *Message) ([]model.SendMessageResult, error)
+	Forward(ctx context.Context, mid string, chatID, userID int64) (model.SendMessageResult, error)
+	SendMessage(ctx context.Context, msg *Message) (*SentMessage, error)
+	WaitAttachmentReady(ctx context.Context, videoToken string, cfg AttachmentWaitConfig) (model.VideoAttachmentDetails, error)
+}
+
+// CallbackAnswerer отвечает на нажатия кнопок. Реализуется Messages.
+type CallbackAnswerer interface {
+	AnswerCallback(ctx context.Context, callbackID string, answer *CallbackAnswer) error
+}
+
+type DownloadAPI interface {
+	DownloadAttachment(ctx context.Context, attach model.Attachment, w io.Writer, opts ...DownloadOpt) (DownloadResult, error)
+}
+
 type SubscriptionsAPI interface {
 	GetSubscriptions(ctx context.Context) (model.GetSubscriptionsResult, error)
 	Subscribe(ctx context.Context, url, secret string, updateTypes []string, version string) (model.SimpleQueryResult, error)
@@ -64,6 +83,18 @@ type UploadAPI interface {
 	Upload(ctx context.Context, uploadType model.UploadType, reader io.Reader, name string, size int64) (string, error)
 }
 
+// ExtendedUploadAPI UploadAPI с загрузкой файлов с диска, по ссылке, частями и пакетами.
+type ExtendedUploadAPI interface {
+	UploadAPI
+	UploadDetailed(ctx context.Context, uploadType model.UploadType, reader io.Reader, name string, size int64, opts ...UploadOpt) (model.UploadResult, error)
+	UploadAuto(ctx context.Context, reader io.Reader, name string, size int64, opts ...UploadOpt) (string, model.AttachmentType, error)
+	UploadFile(ctx context.Context, uploadType model.UploadType, filePath string, opts ...UploadOpt) (string, error)
+	UploadBytes(ctx context.Context, uploadType model.UploadType, name string, data []byte, opts ...UploadOpt) (string, error)
+	UploadFromURL(ctx context.Context, uploadType model.UploadType, url string, opts ...UploadOpt) (string, error)
+	UploadBatch(ctx context.Context, items []UploadItem, parallelism int, opts ...UploadOpt) UploadBatchResults
+	UploadResumable(ctx context.Context, uploadType model.UploadType, src io.Reader, name string, size int64, opts ...UploadOpt) (string, error)
+}
+
 func NewApi(token string, opt ...Opt) (*Api, error) {
 	cli := newClient(token, DefaultHostV2)
 	var err error
@@ -74,11 +105,16 @@ func NewApi(token string, opt ...Opt) (*Api, error) {
 		}
 	}
 
+	upload, messages := newUpload(cli), newMessages(cli)
 	b := &Api{
+		client:        cli,
+		upload:        upload,
+		messages:      messages,
 		Bots:          newBots(cli),
-		Upload:        newUpload(cli),
+		Upload:        upload,
+		Download:      newDownload(cli),
 		Chats:         newChats(cli),
-		Messages:      newMessages(cli),
+		Messages:      messages,
 		Subscriptions: newSubscriptions(cli),
 	}
 
@@ -86,13 +122,36 @@ func NewApi(token string, opt ...Opt) (*Api, error) {
 }
 
 type Api struct {
+	client   *client
+	upload   *Upload
+	messages *Messages
+
 	Bots          BotsAPI
 	Upload        UploadAPI
+	Download      DownloadAPI
 	Chats         ChatsAPI
 	Messages      MessagesAPI
 	Subscriptions SubscriptionsAPI
 }
 
+// ExtendedUpload возвращает Upload, если он реализует ExtendedUploadAPI, иначе загрузчик, созданный NewApi.
+func (a *Api) ExtendedUpload() ExtendedUploadAPI {
+	if upload, ok := a.Upload.(ExtendedUploadAPI); ok {
+		return upload
+	}
+
+	return a.upload
+}
+
+// ExtendedMessages возвращает Messages, если он реализует ExtendedMessagesAPI, иначе Messages, созданный NewApi.
+func (a *Api) ExtendedMessages() ExtendedMessagesAPI {
+	if messages, ok := a.Messages.(ExtendedMessagesAPI); ok {
+		return messages
+	}
+
+	return a.messages
+}
+
 func (a *Api) GetHandler(handler UpdateHandler, secret string) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if handler == nil {
@@ -128,7 +187,12 @@ func (a *Api) GetHandler(handler UpdateHandler, secret string) http.HandlerFunc
 			return
 		}
 
-		handler(r.Context(), update.FromRaw())
+		u := update.FromRaw()
+		if a.client != nil {
+			a.client.onUpdate(r.Context(), u)
+		}
+
+		handler(r.Context(), u)
 	}
 }
 
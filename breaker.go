@@ -0,0 +1,196 @@
+package maxbot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen возвращается без обращения к API, пока circuit breaker группы запросов открыт.
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type CircuitState string
+
+const (
+	CircuitClosed   CircuitState = "closed"
+	CircuitOpen     CircuitState = "open"
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+const (
+	defaultCircuitFailureThreshold = 5
+	defaultCircuitOpenTimeout      = 30 * time.Second
+)
+
+// CircuitBreakerConfig настройки circuit breaker. Нулевые значения заменяются значениями по умолчанию.
+type CircuitBreakerConfig struct {
+	// FailureThreshold количество ошибок подряд, после которого группа запросов блокируется.
+	FailureThreshold int
+	// OpenTimeout время блокировки, после которого пропускается один пробный запрос.
+	OpenTimeout time.Duration
+}
+
+// WithCircuitBreaker включает circuit breaker для каждой группы запросов (/messages, /chats, /updates и т.д.).
+// Ошибками считаются сетевые ошибки, таймауты и ответы 5xx. Таймаут long polling /updates ошибкой
+// не считается: он означает только отсутствие новых событий.
+func WithCircuitBreaker(cfg CircuitBreakerConfig) Opt {
+	return func(c *client) error {
+		c.breaker = newCircuitBreaker(cfg)
+
+		return nil
+	}
+}
+
+type circuitBreaker struct {
+	threshold   int
+	openTimeout time.Duration
+	now         func() time.Time
+
+	mu       sync.Mutex
+	circuits map[string]*circuit
+}
+
+type circuit struct {
+	state    CircuitState
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newCircuitBreaker(cfg CircuitBreakerConfig) *circuitBreaker {
+	b := &circuitBreaker{
+		threshold:   cfg.FailureThreshold,
+		openTimeout: cfg.OpenTimeout,
+		now:         time.Now,
+		circuits:    make(map[string]*circuit),
+	}
+	if b.threshold <= 0 {
+		b.threshold = defaultCircuitFailureThreshold
+	}
+	if b.openTimeout <= 0 {
+		b.openTimeout = defaultCircuitOpenTimeout
+	}
+
+	return b
+}
+
+func (b *circuitBreaker) get(group string) *circuit {
+	c, ok := b.circuits[group]
+	if !ok {
+		c = &circuit{state: CircuitClosed}
+		b.circuits[group] = c
+	}
+
+	return c
+}
+
+// allow проверяет, можно ли выполнить запрос. Возвращает состояние до и после проверки.
+func (b *circuitBreaker) allow(group string) (from, to CircuitState, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.get(group)
+	from = c.state
+
+	switch c.state {
+	case CircuitOpen:
+		if b.now().Sub(c.openedAt) < b.openTimeout {
+			return from, c.state, ErrCircuitOpen
+		}
+		c.state = CircuitHalfOpen
+		c.probing = true
+	case CircuitHalfOpen:
+		if c.probing {
+			return from, c.state, ErrCircuitOpen
+		}
+		c.probing = true
+	}
+
+	return from, c.state, nil
+}
+
+// report учитывает результат запроса. Возвращает состояние до и после.
+func (b *circuitBreaker) report(group string, failed bool) (from, to CircuitState) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	c := b.get(group)
+	from = c.state
+	c.probing = false
+
+	if !failed {
+		c.failures = 0
+		c.state = CircuitClosed
+
+		return from, c.state
+	}
+
+	c.failures++
+	if c.state == CircuitHalfOpen || c.failures >= b.threshold {
+		c.state = CircuitOpen
+		c.openedAt = b.now()
+	}
+
+	return from, c.state
+}
+
+// release снимает блокировку пробного запроса, не учитывая его результат.
+func (b *circuitBreaker) release(group string) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.get(group).probing = false
+}
+
+func circuitGroup(endpoint string) string {
+	group, _, _ := strings.Cut(strings.TrimPrefix(endpoint, "/"), "/")
+
+	return "/" + group
+}
+
+// isCircuitFailure определяет, свидетельствует ли ошибка о недоступности API.
+func isCircuitFailure(group string, statusCode int, err error) bool {
+	if err == nil {
+		return false
+	}
+
+	var (
+		timeoutErr *TimeoutError
+		networkErr *NetworkError
+	)
+	if errors.As(err, &timeoutErr) {
+		// long polling без новых событий может завершиться таймаутом клиента
+		return group != pathUpdates
+	}
+	if errors.As(err, &networkErr) {
+		return true
+	}
+
+	return statusCode >= http.StatusInternalServerError
+}
+
+func (c *client) checkCircuit(ctx context.Context, group string) error {
+	from, to, err := c.breaker.allow(group)
+	if from != to {
+		c.onCircuitStateChange(ctx, group, from, to)
+	}
+
+	return err
+}
+
+func (c *client) reportCircuit(ctx context.Context, group string, statusCode int, err error) {
+	// запрос отменён вызывающей стороной: результат ничего не говорит о состоянии API
+	if err != nil && ctx.Err() != nil {
+		c.breaker.release(group)
+
+		return
+	}
+
+	from, to := c.breaker.report(group, isCircuitFailure(group, statusCode, err))
+	if from != to {
+		c.onCircuitStateChange(ctx, group, from, to)
+	}
+}
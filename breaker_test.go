@@ -0,0 +1,163 @@
+package maxbot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCircuitBreaker_States(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := newCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 2, OpenTimeout: time.Minute})
+	b.now = func() time.Time { return now }
+
+	_, _, err := b.allow("/messages")
+	require.NoError(t, err)
+	from, to := b.report("/messages", true)
+	assert.Equal(t, CircuitClosed, from)
+	assert.Equal(t, CircuitClosed, to)
+
+	_, to = b.report("/messages", true)
+	assert.Equal(t, CircuitOpen, to)
+
+	_, _, err = b.allow("/messages")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	// другие группы не блокируются
+	_, _, err = b.allow("/chats")
+	assert.NoError(t, err)
+
+	now = now.Add(time.Minute)
+	from, to, err = b.allow("/messages")
+	require.NoError(t, err)
+	assert.Equal(t, CircuitOpen, from)
+	assert.Equal(t, CircuitHalfOpen, to)
+
+	// пока идёт пробный запрос, остальные отклоняются
+	_, _, err = b.allow("/messages")
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+
+	_, to = b.report("/messages", true)
+	assert.Equal(t, CircuitOpen, to)
+
+	now = now.Add(time.Minute)
+	_, _, err = b.allow("/messages")
+	require.NoError(t, err)
+	_, to = b.report("/messages", false)
+	assert.Equal(t, CircuitClosed, to)
+}
+
+func TestCircuitBreaker_Release(t *testing.T) {
+	now := time.Unix(0, 0)
+	b := newCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: time.Second})
+	b.now = func() time.Time { return now }
+
+	b.report("/chats", true)
+	now = now.Add(time.Second)
+
+	_, _, err := b.allow("/chats")
+	require.NoError(t, err)
+	b.release("/chats")
+
+	from, to, err := b.allow("/chats")
+	require.NoError(t, err)
+	assert.Equal(t, CircuitHalfOpen, from)
+	assert.Equal(t, CircuitHalfOpen, to)
+}
+
+func TestCircuitGroup(t *testing.T) {
+	assert.Equal(t, "/chats", circuitGroup("/chats/{id}/members"))
+	assert.Equal(t, "/messages", circuitGroup("/messages"))
+	assert.Equal(t, "/me", circuitGroup("/me"))
+}
+
+func TestIsCircuitFailure(t *testing.T) {
+	assert.False(t, isCircuitFailure("/me", http.StatusOK, nil))
+	assert.False(t, isCircuitFailure("/me", http.StatusBadRequest, &Error{Code: "bad.request"}))
+	assert.True(t, isCircuitFailure("/me", http.StatusBadGateway, &Error{Code: "internal"}))
+	assert.True(t, isCircuitFailure("/me", 0, &NetworkError{Op: "GET /me", Err: errors.New("refused")}))
+	assert.True(t, isCircuitFailure("/me", 0, &TimeoutError{Op: "GET /me"}))
+	assert.False(t, isCircuitFailure(pathUpdates, 0, &TimeoutError{Op: "GET /updates"}))
+	assert.True(t, isCircuitFailure(pathUpdates, 0, &NetworkError{Op: "GET /updates", Err: errors.New("refused")}))
+}
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	var count int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"code":"internal","message":"unavailable"}`))
+	}))
+	defer srv.Close()
+
+	type transition struct {
+		group    string
+		from, to CircuitState
+	}
+	var transitions []transition
+	hook := Hook{
+		OnCircuitStateChange: func(ctx context.Context, group string, from, to CircuitState) {
+			transitions = append(transitions, transition{group, from, to})
+		},
+	}
+
+	api, err := NewApi(testToken,
+		WithBaseURL(srv.URL),
+		WithHook(hook),
+		WithCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 2, OpenTimeout: time.Hour}),
+	)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	for i := 0; i < 2; i++ {
+		_, err = api.Chats.GetChat(ctx, 1)
+		require.Error(t, err)
+		assert.NotErrorIs(t, err, ErrCircuitOpen)
+	}
+
+	_, err = api.Chats.GetChat(ctx, 1)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 2, count)
+
+	_, err = api.Messages.Send(ctx, NewMessage().SetChat(1).SetText("text"))
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 3, count)
+
+	assert.Equal(t, []transition{{"/chats", CircuitClosed, CircuitOpen}}, transitions)
+}
+
+func TestSubscriptions_GetUpdates_CircuitOpen(t *testing.T) {
+	var count, retries int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte(`{"code":"internal","message":"unavailable"}`))
+	}))
+	defer srv.Close()
+
+	api, err := NewApi(testToken,
+		WithBaseURL(srv.URL),
+		WithHook(Hook{OnRetry: func(context.Context, RequestInfo, int, error) { retries++ }}),
+		WithCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1, OpenTimeout: time.Hour}),
+	)
+	require.NoError(t, err)
+
+	// первая ошибка открывает circuit, повтор после паузы отклоняется без ожидания следующих
+	_, _, err = api.Subscriptions.GetUpdates(context.Background(), 0)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Equal(t, 1, retries)
+
+	start := time.Now()
+	_, _, err = api.Subscriptions.GetUpdates(context.Background(), 0)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, 1, retries)
+}
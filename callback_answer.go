@@ -0,0 +1,81 @@
+package maxbot
+
+import (
+	"context"
+	"errors"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+// ErrEmptyCallbackAnswer ответ на нажатие кнопки не содержит ни сообщения, ни уведомления.
+var ErrEmptyCallbackAnswer = errors.New("callback answer must contain message or notification")
+
+// CallbackAnswer ответ на нажатие кнопки: новое содержимое сообщения с кнопкой, всплывающее
+// уведомление или оба сразу.
+type CallbackAnswer struct {
+	message      *Message
+	notification *string
+	// ack пустой ответ разрешён: нажатие только подтверждается
+	ack bool
+}
+
+func NewCallbackAnswer() *CallbackAnswer {
+	return &CallbackAnswer{}
+}
+
+// NewCallbackAck создаёт ответ, который только подтверждает нажатие: сообщение не меняется,
+// уведомление не показывается.
+func NewCallbackAck() *CallbackAnswer {
+	return &CallbackAnswer{ack: true}
+}
+
+// SetMessage заменяет сообщение, к которому была прикреплена кнопка. Получатель msg не используется.
+func (a *CallbackAnswer) SetMessage(msg *Message) *CallbackAnswer {
+	a.message = msg
+
+	return a
+}
+
+// SetNotification показывает пользователю уведомление.
+func (a *CallbackAnswer) SetNotification(text string) *CallbackAnswer {
+	a.notification = &text
+
+	return a
+}
+
+// Build возвращает тело запроса. Возвращает ErrEmptyCallbackAnswer, если не задано ни сообщение, ни уведомление
+// и ответ создан не NewCallbackAck.
+func (a *CallbackAnswer) Build() (res model.CallbackAnswer, err error) {
+	if a.message != nil {
+		body := a.message.MessageBody()
+		res.Message = &body
+	}
+	res.Notification = a.notification
+
+	if !a.ack && res.Message == nil && res.Notification == nil {
+		err = ErrEmptyCallbackAnswer
+	}
+
+	return
+}
+
+// AnswerCallback отвечает на нажатие кнопки callbackID.
+func (m *Messages) AnswerCallback(ctx context.Context, callbackID string, answer *CallbackAnswer) error {
+	if answer == nil {
+		return ErrEmptyCallbackAnswer
+	}
+
+	req, err := answer.Build()
+	if err != nil {
+		return err
+	}
+	if m.client.validateMessages && answer.message != nil {
+		if errs := answer.message.validateBody(); len(errs) > 0 {
+			return errs
+		}
+	}
+
+	res, err := m.AnswerOnCallback(ctx, callbackID, req)
+
+	return simpleResultError("answer callback", res, err)
+}
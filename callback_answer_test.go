@@ -0,0 +1,81 @@
+package maxbot
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+func TestCallbackAnswer_Build(t *testing.T) {
+	_, err := NewCallbackAnswer().Build()
+	assert.ErrorIs(t, err, ErrEmptyCallbackAnswer)
+
+	res, err := NewCallbackAck().Build()
+	require.NoError(t, err)
+	assert.Equal(t, model.CallbackAnswer{}, res)
+
+	res, err = NewCallbackAnswer().SetNotification("saved").Build()
+	require.NoError(t, err)
+	assert.Nil(t, res.Message)
+	assert.Equal(t, "saved", *res.Notification)
+
+	kb := model.NewKeyboard()
+	kb.AddRow().AddCallBack("Back", "back")
+	msg := NewMessage().SetUser(1).SetText("menu").AddKeyboard(kb)
+
+	res, err = NewCallbackAnswer().SetMessage(msg).Build()
+	require.NoError(t, err)
+	assert.Nil(t, res.Notification)
+	require.NotNil(t, res.Message)
+	assert.Equal(t, "menu", res.Message.Text)
+	require.Len(t, res.Message.Attachments, 1)
+	assert.Equal(t, model.AttachInlineKeyboard, res.Message.Attachments[0].Type)
+}
+
+func TestMessages_AnswerCallback(t *testing.T) {
+	var query, body string
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		data, _ := io.ReadAll(r.Body)
+		query, body = r.URL.RawQuery, string(data)
+		_, _ = w.Write([]byte(`{"success":true}`))
+	}))
+	defer srv.Close()
+
+	api, err := NewApi(testToken, WithBaseURL(srv.URL), WithValidation())
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	answer := NewCallbackAnswer().SetMessage(NewMessage().SetText("edited")).SetNotification("done")
+	require.NoError(t, api.ExtendedMessages().AnswerCallback(ctx, "cb.1", answer))
+	assert.Equal(t, "callback_id=cb.1", query)
+	assert.JSONEq(t, `{"message":{"text":"edited","attachments":null},"notification":"done"}`, body)
+
+	require.NoError(t, api.ExtendedMessages().AnswerCallback(ctx, "cb.1", NewCallbackAck()))
+	assert.JSONEq(t, `{}`, body)
+
+	err = api.ExtendedMessages().AnswerCallback(ctx, "cb.1", NewCallbackAnswer())
+	assert.ErrorIs(t, err, ErrEmptyCallbackAnswer)
+
+	// AnswerOnCallback отправляет ответ без проверки
+	_, err = api.Messages.AnswerOnCallback(ctx, "cb.1", model.CallbackAnswer{})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{}`, body)
+
+	kb := model.NewKeyboard()
+	kb.AddRow()
+	err = api.ExtendedMessages().AnswerCallback(ctx, "cb.1", NewCallbackAnswer().SetMessage(NewMessage().AddKeyboard(kb)))
+	var errs ValidationErrors
+	assert.True(t, errors.As(err, &errs))
+
+	assert.Equal(t, 3, requests)
+}
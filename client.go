@@ -22,6 +22,13 @@ type client struct {
 	httpClient  HttpClient
 	pollPause   time.Duration
 	pollTimeout time.Duration
+	hooks       []Hook
+	breaker     *circuitBreaker
+	uploadCache *uploadCache
+	// attachmentWait настройки повтора отправки, пока вложение обрабатывается; nil - 3 попытки
+	attachmentWait *attachmentWait
+	// validateMessages проверять сообщение перед отправкой, см. WithValidation
+	validateMessages bool
 }
 
 func newClient(token, host string) *client {
@@ -40,32 +47,71 @@ func newClient(token, host string) *client {
 }
 
 func (c *client) rawWithRetry(ctx context.Context, method, path string, query url.Values, in, out any) error {
-	var err error
-	for attempt := 0; attempt < maxRetries; attempt++ {
-		err = c.raw(ctx, method, path, query, in, out)
-		if err == nil {
-			return nil
+	wait := c.attachmentWait
+	if wait == nil {
+		wait = defaultAttachmentWait()
+	}
+
+	// сетевые ошибки повторяются не больше maxRetries раз независимо от настроек ожидания вложения:
+	// запрос мог дойти до сервера, и каждый повтор может отправить сообщение ещё раз
+	var failures int
+	err := wait.poll(ctx, func() error {
+		return c.raw(ctx, method, path, query, in, out)
+	}, func(err error) bool {
+		if isAttachmentNotReady(err) {
+			return true
 		}
 
 		apiErr := &Error{}
-		if errors.As(err, &apiErr) && !apiErr.IsAttachmentNotReady() {
-			return fmt.Errorf("sending message failed: %w", err)
+		if errors.Is(err, ErrCircuitOpen) || errors.As(err, &apiErr) {
+			return false
 		}
+		failures++
 
-		retryWait := time.Duration(1<<uint(attempt)) * time.Second
-		if attempt < maxRetries-1 {
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case <-time.After(retryWait):
-			}
-		}
+		return failures < maxRetries
+	}, func(attempt int, err error) {
+		c.onRetry(ctx, newRequestInfo(method, path, query), attempt, err)
+	})
+
+	apiErr := &Error{}
+	if err != nil && errors.As(err, &apiErr) && !apiErr.IsAttachmentNotReady() {
+		return fmt.Errorf("sending message failed: %w", err)
 	}
 
 	return err
 }
 
 func (c *client) raw(ctx context.Context, method, path string, query url.Values, in, out any) error {
+	info := newRequestInfo(method, path, query)
+	ctx = c.onRequest(ctx, info)
+
+	var group string
+	if c.breaker != nil {
+		group = circuitGroup(info.Endpoint)
+		if err := c.checkCircuit(ctx, group); err != nil {
+			err = fmt.Errorf("%s %s: %w", method, path, err)
+			c.onResponse(ctx, info, ResponseInfo{Err: err})
+
+			return err
+		}
+	}
+
+	start := time.Now()
+	statusCode, err := c.rawRequest(ctx, method, path, query, in, out)
+	if c.breaker != nil {
+		c.reportCircuit(ctx, group, statusCode, err)
+	}
+
+	c.onResponse(ctx, info, ResponseInfo{
+		StatusCode: statusCode,
+		Duration:   time.Since(start),
+		Err:        err,
+	})
+
+	return err
+}
+
+func (c *client) rawRequest(ctx context.Context, method, path string, query url.Values, in, out any) (int, error) {
 	u := c.baseURL
 	u.Path = path
 
@@ -75,14 +121,14 @@ func (c *client) raw(ctx context.Context, method, path string, query url.Values,
 	if in != nil {
 		data, err := json.Marshal(in)
 		if err != nil {
-			return err
+			return 0, err
 		}
 		body = bytes.NewReader(data)
 	}
 
 	req, err := http.NewRequestWithContext(ctx, method, u.String(), body)
 	if err != nil {
-		return fmt.Errorf("failed to create request: %w", err)
+		return 0, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	req.Header.Set(AuthorizationHeader, c.token)
@@ -96,14 +142,14 @@ func (c *client) raw(ctx context.Context, method, path string, query url.Values,
 		var urlErr *url.Error
 		if errors.As(err, &urlErr) {
 			if urlErr.Timeout() {
-				return &TimeoutError{
+				return 0, &TimeoutError{
 					fmt.Sprintf("%s %s", method, path),
 					"request timeout exceeded",
 				}
 			}
 		}
 
-		return &NetworkError{
+		return 0, &NetworkError{
 			Op:  fmt.Sprintf("%s %s", method, path),
 			Err: err,
 		}
@@ -111,14 +157,14 @@ func (c *client) raw(ctx context.Context, method, path string, query url.Values,
 
 	defer func() { _ = resp.Body.Close() }()
 	if c.isNotOk(resp.StatusCode) {
-		return parseResponseError(resp)
+		return resp.StatusCode, parseResponseError(resp)
 	}
 
 	if out != nil {
-		return json.NewDecoder(resp.Body).Decode(out)
+		return resp.StatusCode, json.NewDecoder(resp.Body).Decode(out)
 	}
 
-	return nil
+	return resp.StatusCode, nil
 }
 
 func (c *client) do(req *http.Request) (*http.Response, error) {
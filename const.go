@@ -18,6 +18,8 @@ const (
 	defaultTimeout  = 30 * time.Second
 	defaultPause    = time.Second
 	maxUpdatesLimit = 50
+
+	defaultChunkSize = 5 << 20
 )
 
 const (
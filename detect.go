@@ -0,0 +1,114 @@
+package maxbot
+
+import (
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+const (
+	sniffLen           = 512
+	defaultContentType = "application/octet-stream"
+)
+
+// extensionTypes дополняет встроенную таблицу mime, в которой нет большинства аудио- и видеоформатов.
+var extensionTypes = map[string]string{
+	".jpg":  "image/jpeg",
+	".jpeg": "image/jpeg",
+	".png":  "image/png",
+	".gif":  "image/gif",
+	".webp": "image/webp",
+	".heic": "image/heic",
+	".bmp":  "image/bmp",
+	".mp4":  "video/mp4",
+	".m4v":  "video/mp4",
+	".mov":  "video/quicktime",
+	".avi":  "video/x-msvideo",
+	".mkv":  "video/x-matroska",
+	".webm": "video/webm",
+	".3gp":  "video/3gpp",
+	".mp3":  "audio/mpeg",
+	".m4a":  "audio/mp4",
+	".aac":  "audio/aac",
+	".ogg":  "audio/ogg",
+	".oga":  "audio/ogg",
+	".opus": "audio/opus",
+	".wav":  "audio/wav",
+	".flac": "audio/flac",
+	".amr":  "audio/amr",
+	".pdf":  "application/pdf",
+	".zip":  "application/zip",
+	".txt":  "text/plain; charset=utf-8",
+}
+
+// DetectUploadType определяет тип загрузки и MIME-тип по содержимому файла и расширению имени.
+// head - начало файла, достаточно первых 512 байт.
+func DetectUploadType(name string, head []byte) (model.UploadType, string) {
+	contentType := detectContentType(name, head)
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return model.UploadFile, contentType
+	}
+
+	switch {
+	case mediaType == "image/svg+xml":
+		return model.UploadFile, contentType
+	case strings.HasPrefix(mediaType, "image/"):
+		return model.UploadImage, contentType
+	case strings.HasPrefix(mediaType, "video/"):
+		return model.UploadVideo, contentType
+	case strings.HasPrefix(mediaType, "audio/"):
+		return model.UploadAudio, contentType
+	}
+
+	return model.UploadFile, contentType
+}
+
+func detectContentType(name string, head []byte) string {
+	byExtension := extensionContentType(name)
+
+	if len(head) > 0 {
+		sniffed := http.DetectContentType(head)
+		switch {
+		case isAmbiguousContainer(sniffed):
+			// по контейнеру не отличить звук от видео, расширение уточняет тип
+			if strings.HasPrefix(byExtension, "audio/") {
+				return byExtension
+			}
+
+			return sniffed
+		case !isGenericContentType(sniffed):
+			return sniffed
+		}
+	}
+
+	if byExtension != "" {
+		return byExtension
+	}
+
+	return defaultContentType
+}
+
+func extensionContentType(name string) string {
+	ext := strings.ToLower(filepath.Ext(name))
+	if contentType, ok := extensionTypes[ext]; ok {
+		return contentType
+	}
+
+	return mime.TypeByExtension(ext)
+}
+
+// isGenericContentType сообщает, что сигнатура содержимого не распознана и стоит проверить расширение.
+func isGenericContentType(contentType string) bool {
+	return contentType == defaultContentType || strings.HasPrefix(contentType, "text/plain")
+}
+
+// isAmbiguousContainer сообщает, что контейнер может содержать как звук, так и видео:
+// Ogg для голосовых сообщений и MP4 для m4a.
+func isAmbiguousContainer(contentType string) bool {
+	return contentType == "application/ogg" || contentType == "video/mp4"
+}
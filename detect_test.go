@@ -0,0 +1,100 @@
+package maxbot
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+var (
+	pngHeader = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	mp3Header = []byte("ID3\x03\x00\x00\x00\x00\x00\x00")
+	mp4Header = []byte("\x00\x00\x00\x18ftypmp42\x00\x00\x00\x00mp42isom")
+	pdfHeader = []byte("%PDF-1.7\n")
+	oggHeader = []byte("OggS\x00\x02\x00\x00\x00\x00\x00\x00\x00\x00")
+	m4aHeader = []byte("\x00\x00\x00\x20ftypM4A \x00\x00\x00\x00M4A mp42isom\x00\x00\x00\x00")
+)
+
+func TestDetectUploadType(t *testing.T) {
+	tests := []struct {
+		name         string
+		fileName     string
+		head         []byte
+		expectedType model.UploadType
+		expectedMIME string
+	}{
+		{"png by content", "logo", pngHeader, model.UploadImage, "image/png"},
+		{"png by content with wrong extension", "logo.pdf", pngHeader, model.UploadImage, "image/png"},
+		{"mp3 by content", "track", mp3Header, model.UploadAudio, "audio/mpeg"},
+		{"mp4 by content", "clip.bin", mp4Header, model.UploadVideo, "video/mp4"},
+		{"pdf by content", "doc", pdfHeader, model.UploadFile, "application/pdf"},
+		{"jpeg by extension", "photo.JPG", nil, model.UploadImage, "image/jpeg"},
+		{"mov by extension", "clip.mov", []byte{0, 1, 2, 3}, model.UploadVideo, "video/quicktime"},
+		{"m4a by extension", "voice.m4a", nil, model.UploadAudio, "audio/mp4"},
+		{"m4a container with audio extension", "song.m4a", m4aHeader, model.UploadAudio, "audio/mp4"},
+		{"mp4 container with video extension", "clip.mp4", m4aHeader, model.UploadVideo, "video/mp4"},
+		{"ogg container with ogg extension", "voice.ogg", oggHeader, model.UploadAudio, "audio/ogg"},
+		{"ogg container with opus extension", "voice.opus", oggHeader, model.UploadAudio, "audio/opus"},
+		{"ogg container without extension", "voice", oggHeader, model.UploadFile, "application/ogg"},
+		{"svg is file", "icon.svg", nil, model.UploadFile, "image/svg+xml"},
+		{"unknown", "data", []byte{0, 1, 2, 3}, model.UploadFile, defaultContentType},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			uploadType, contentType := DetectUploadType(tt.fileName, tt.head)
+			assert.Equal(t, tt.expectedType, uploadType)
+			assert.Equal(t, tt.expectedMIME, contentType)
+		})
+	}
+}
+
+func TestUploadType_AttachmentType(t *testing.T) {
+	assert.Equal(t, model.AttachImage, model.UploadImage.AttachmentType())
+	assert.Equal(t, model.AttachVideo, model.UploadVideo.AttachmentType())
+	assert.Equal(t, model.AttachAudio, model.UploadAudio.AttachmentType())
+	assert.Equal(t, model.AttachFile, model.UploadFile.AttachmentType())
+}
+
+func TestUpload_UploadAuto(t *testing.T) {
+	var (
+		requestedType   string
+		partContentType string
+	)
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case pathUpload:
+			requestedType = r.URL.Query().Get(paramType)
+			_, _ = w.Write([]byte(`{"url":"` + srv.URL + `/upload-target"}`))
+		default:
+			file, header, err := r.FormFile(fieldData)
+			require.NoError(t, err)
+			data, _ := io.ReadAll(file)
+			assert.Equal(t, pngHeader, data)
+			partContentType = header.Header.Get("Content-Type")
+			_, _ = w.Write([]byte(`{"photos":{"a":{"token":"image_token"}}}`))
+		}
+	}))
+	defer srv.Close()
+
+	api, err := NewApi(testToken, WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	token, at, err := api.ExtendedUpload().UploadAuto(context.Background(), io.MultiReader(bytes.NewReader(pngHeader)), "logo", int64(len(pngHeader)))
+	require.NoError(t, err)
+
+	assert.Equal(t, "image_token", token)
+	assert.Equal(t, model.AttachImage, at)
+	assert.Equal(t, string(model.UploadImage), requestedType)
+	assert.Equal(t, "image/png", partContentType)
+}
@@ -0,0 +1,172 @@
+package maxbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+// ErrDownloadTooLarge возвращается, если файл больше ограничения WithMaxDownloadSize.
+var ErrDownloadTooLarge = errors.New("download: file exceeds size limit")
+
+type Download struct {
+	client   *client
+	messages *Messages
+}
+
+func newDownload(cli *client) *Download {
+	return &Download{
+		client:   cli,
+		messages: newMessages(cli),
+	}
+}
+
+type DownloadOpt func(o *downloadOptions)
+
+type downloadOptions struct {
+	maxSize        int64
+	maxVideoHeight int
+}
+
+// WithMaxDownloadSize ограничивает размер скачиваемого файла в байтах.
+func WithMaxDownloadSize(size int64) DownloadOpt {
+	return func(o *downloadOptions) {
+		o.maxSize = size
+	}
+}
+
+// WithMaxVideoQuality выбирает лучшее качество видео с высотой кадра не больше height: 1080, 720, 480, 360, 240 или 144.
+func WithMaxVideoQuality(height int) DownloadOpt {
+	return func(o *downloadOptions) {
+		o.maxVideoHeight = height
+	}
+}
+
+// DownloadResult сведения о скачанном файле.
+type DownloadResult struct {
+	// URL ссылка, по которой скачан файл.
+	URL string
+	// ContentType тип содержимого из ответа сервера.
+	ContentType string
+	// ContentLength количество записанных байт.
+	ContentLength int64
+}
+
+// DownloadAttachment скачивает вложение и записывает его в w. Для видео ссылка выбирается
+// через GetVideoAttachmentDetails. Если файл превышает ограничение размера, возвращается
+// ErrDownloadTooLarge, а в w может остаться начало файла.
+func (d *Download) DownloadAttachment(ctx context.Context, attach model.Attachment, w io.Writer, opts ...DownloadOpt) (res DownloadResult, err error) {
+	o := downloadOptions{}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	res.URL, err = d.attachmentURL(ctx, attach, o)
+	if err != nil {
+		return
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, res.URL, nil)
+	if err != nil {
+		err = fmt.Errorf("failed to create request: %w", err)
+
+		return
+	}
+
+	resp, err := d.client.do(req)
+	if err != nil {
+		err = &NetworkError{Op: "download attachment", Err: err}
+
+		return
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if d.client.isNotOk(resp.StatusCode) {
+		err = fmt.Errorf("download attachment: unexpected status %s", resp.Status)
+
+		return
+	}
+
+	res.ContentType = resp.Header.Get("Content-Type")
+	if o.maxSize > 0 && resp.ContentLength > o.maxSize {
+		err = fmt.Errorf("%w: %d > %d bytes", ErrDownloadTooLarge, resp.ContentLength, o.maxSize)
+
+		return
+	}
+
+	var body io.Reader = resp.Body
+	if o.maxSize > 0 {
+		// лишний байт показывает, что файл больше ограничения
+		body = io.LimitReader(resp.Body, o.maxSize+1)
+	}
+
+	res.ContentLength, err = io.Copy(w, body)
+	if err != nil {
+		err = fmt.Errorf("download attachment: %w", err)
+
+		return
+	}
+	if o.maxSize > 0 && res.ContentLength > o.maxSize {
+		err = fmt.Errorf("%w: %d bytes", ErrDownloadTooLarge, o.maxSize)
+	}
+
+	return
+}
+
+func (d *Download) attachmentURL(ctx context.Context, attach model.Attachment, o downloadOptions) (string, error) {
+	switch attach.Type {
+	case model.AttachVideo:
+		if attach.Payload.Token != "" {
+			details, err := d.messages.GetVideoAttachmentDetails(ctx, attach.Payload.Token)
+			if err != nil {
+				return "", fmt.Errorf("get video details: %w", err)
+			}
+			if link := bestVideoURL(details.Urls, o.maxVideoHeight); link != "" {
+				return link, nil
+			}
+			// HLS - это плейлист, а не файл, поэтому без подходящего mp4 используется исходная ссылка
+			if o.maxVideoHeight > 0 && bestVideoURL(details.Urls, 0) != "" {
+				return "", fmt.Errorf("download: no mp4 rendition within %dp", o.maxVideoHeight)
+			}
+		}
+	case model.AttachImage, model.AttachAudio, model.AttachFile, model.AttachSticker:
+	default:
+		return "", fmt.Errorf("download: attachment type %q has no file", attach.Type)
+	}
+
+	if attach.Payload.URL == "" {
+		return "", fmt.Errorf("download: attachment %q has no url", attach.Type)
+	}
+
+	return attach.Payload.URL, nil
+}
+
+// bestVideoURL выбирает mp4 наибольшего качества с высотой не больше maxHeight (0 - без ограничения).
+func bestVideoURL(urls *model.VideoUrls, maxHeight int) string {
+	if urls == nil {
+		return ""
+	}
+
+	qualities := []struct {
+		height int
+		url    *string
+	}{
+		{1080, urls.Mp41080},
+		{720, urls.Mp4720},
+		{480, urls.Mp4480},
+		{360, urls.Mp4360},
+		{240, urls.Mp4240},
+		{144, urls.Mp4144},
+	}
+	for _, q := range qualities {
+		if q.url != nil && *q.url != "" && (maxHeight <= 0 || q.height <= maxHeight) {
+			return *q.url
+		}
+	}
+
+	return ""
+}
@@ -0,0 +1,144 @@
+package maxbot
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+func newDownloadServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/videos/video_token":
+			_, _ = w.Write([]byte(`{"token":"video_token","urls":{` +
+				`"mp4_1080":"` + srv.URL + `/v1080.mp4",` +
+				`"mp4_480":"` + srv.URL + `/v480.mp4",` +
+				`"hls":"` + srv.URL + `/playlist.m3u8"}}`))
+		case "/videos/hls_token":
+			_, _ = w.Write([]byte(`{"token":"hls_token","urls":{"hls":"` + srv.URL + `/playlist.m3u8"}}`))
+		case "/v1080.mp4", "/v480.mp4":
+			w.Header().Set("Content-Type", "video/mp4")
+			_, _ = w.Write([]byte(strings.TrimPrefix(r.URL.Path, "/")))
+		case "/playlist.m3u8":
+			_, _ = w.Write([]byte("#EXTM3U"))
+		case "/original.mp4":
+			_, _ = w.Write([]byte("original"))
+		case "/file.pdf":
+			w.Header().Set("Content-Type", "application/pdf")
+			_, _ = w.Write([]byte("pdf content"))
+		case "/stream":
+			w.WriteHeader(http.StatusOK)
+			// без Content-Length: размер проверяется при чтении
+			w.(http.Flusher).Flush()
+			_, _ = w.Write([]byte("streamed content"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	return srv
+}
+
+func TestDownload_DownloadAttachment(t *testing.T) {
+	srv := newDownloadServer(t)
+	defer srv.Close()
+
+	api, err := NewApi(testToken, WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	tests := []struct {
+		name        string
+		attach      model.Attachment
+		opts        []DownloadOpt
+		expected    string
+		contentType string
+		errContains string
+	}{
+		{
+			name:        "file",
+			attach:      model.Attachment{Type: model.AttachFile, Payload: model.Payload{URL: srv.URL + "/file.pdf"}},
+			expected:    "pdf content",
+			contentType: "application/pdf",
+		},
+		{
+			name:        "best video quality",
+			attach:      model.Attachment{Type: model.AttachVideo, Payload: model.Payload{Token: "video_token"}},
+			expected:    "v1080.mp4",
+			contentType: "video/mp4",
+		},
+		{
+			name:     "video quality limit",
+			attach:   model.Attachment{Type: model.AttachVideo, Payload: model.Payload{Token: "video_token"}},
+			opts:     []DownloadOpt{WithMaxVideoQuality(720)},
+			expected: "v480.mp4",
+		},
+		{
+			name:        "no mp4 within quality limit",
+			attach:      model.Attachment{Type: model.AttachVideo, Payload: model.Payload{Token: "video_token"}},
+			opts:        []DownloadOpt{WithMaxVideoQuality(100)},
+			errContains: "no mp4 rendition within 100p",
+		},
+		{
+			name:     "hls only falls back to payload url",
+			attach:   model.Attachment{Type: model.AttachVideo, Payload: model.Payload{Token: "hls_token", URL: srv.URL + "/original.mp4"}},
+			expected: "original",
+		},
+		{
+			name:        "hls only without payload url",
+			attach:      model.Attachment{Type: model.AttachVideo, Payload: model.Payload{Token: "hls_token"}},
+			errContains: "has no url",
+		},
+		{
+			name:        "size limit by content length",
+			attach:      model.Attachment{Type: model.AttachFile, Payload: model.Payload{URL: srv.URL + "/file.pdf"}},
+			opts:        []DownloadOpt{WithMaxDownloadSize(5)},
+			errContains: ErrDownloadTooLarge.Error(),
+		},
+		{
+			name:        "size limit while streaming",
+			attach:      model.Attachment{Type: model.AttachFile, Payload: model.Payload{URL: srv.URL + "/stream"}},
+			opts:        []DownloadOpt{WithMaxDownloadSize(5)},
+			errContains: ErrDownloadTooLarge.Error(),
+		},
+		{
+			name:        "not found",
+			attach:      model.Attachment{Type: model.AttachImage, Payload: model.Payload{URL: srv.URL + "/missing"}},
+			errContains: "unexpected status",
+		},
+		{
+			name:        "attachment without file",
+			attach:      model.Attachment{Type: model.AttachLocation},
+			errContains: "has no file",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			res, err := api.Download.DownloadAttachment(context.Background(), tt.attach, buf, tt.opts...)
+			if tt.errContains != "" {
+				assert.ErrorContains(t, err, tt.errContains)
+
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, buf.String())
+			assert.Equal(t, int64(len(tt.expected)), res.ContentLength)
+			if tt.contentType != "" {
+				assert.Equal(t, tt.contentType, res.ContentType)
+			}
+		})
+	}
+}
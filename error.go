@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strings"
 )
 
 type Error struct {
@@ -20,6 +21,11 @@ func (e Error) IsAttachmentNotReady() bool {
 	return e.Code == "attachment.not.ready"
 }
 
+// IsAttachmentError сообщает об ошибке вложения, кроме незавершённой обработки файла.
+func (e Error) IsAttachmentError() bool {
+	return strings.HasPrefix(e.Code, "attachment") && !e.IsAttachmentNotReady()
+}
+
 func parseResponseError(resp *http.Response) error {
 	responseErr := &Error{}
 
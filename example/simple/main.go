@@ -148,17 +148,12 @@ func mdHandler(ctx context.Context, api *maxbot.Api, update model.Update) {
 }
 
 func videoHandler(ctx context.Context, api *maxbot.Api, update model.Update) {
-	f, err := uploadStore.Open("upload/video.mp4")
+	data, err := uploadStore.ReadFile("upload/video.mp4")
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	info, err := f.Stat()
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	token, err := api.Upload.Upload(ctx, model.UploadVideo, f, info.Name(), info.Size())
+	token, err := api.ExtendedUpload().UploadBytes(ctx, model.UploadVideo, "video.mp4", data)
 	if err != nil {
 		log.Println("upload error:", err)
 	}
@@ -171,17 +166,12 @@ func videoHandler(ctx context.Context, api *maxbot.Api, update model.Update) {
 }
 
 func fileHandler(ctx context.Context, api *maxbot.Api, update model.Update) {
-	f, err := uploadStore.Open("upload/video.mp4")
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	info, err := f.Stat()
+	data, err := uploadStore.ReadFile("upload/video.mp4")
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	token, err := api.Upload.Upload(ctx, model.UploadFile, f, info.Name(), info.Size())
+	token, err := api.ExtendedUpload().UploadBytes(ctx, model.UploadFile, "video.mp4", data)
 	if err != nil {
 		log.Println("upload error:", err)
 
@@ -196,17 +186,12 @@ func fileHandler(ctx context.Context, api *maxbot.Api, update model.Update) {
 }
 
 func audioHandler(ctx context.Context, api *maxbot.Api, update model.Update) {
-	f, err := uploadStore.Open("upload/music.mp3")
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	info, err := f.Stat()
+	data, err := uploadStore.ReadFile("upload/music.mp3")
 	if err != nil {
 		log.Fatal(err)
 	}
 
-	token, err := api.Upload.Upload(ctx, model.UploadAudio, f, info.Name(), info.Size())
+	token, err := api.ExtendedUpload().UploadBytes(ctx, model.UploadAudio, "music.mp3", data)
 	if err != nil {
 		log.Println("upload error:", err)
 
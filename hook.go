@@ -0,0 +1,149 @@
+package maxbot
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+// Hook набор обработчиков событий клиента. Любое из полей может быть nil.
+type Hook struct {
+	// OnRequest вызывается перед запросом к API. Возвращённый контекст используется для запроса
+	// и передаётся в OnResponse.
+	OnRequest func(ctx context.Context, req RequestInfo) context.Context
+	// OnResponse вызывается после завершения запроса к API, в том числе неуспешного.
+	OnResponse func(ctx context.Context, req RequestInfo, res ResponseInfo)
+	// OnRetry вызывается перед повторной попыткой запроса.
+	OnRetry func(ctx context.Context, req RequestInfo, attempt int, err error)
+	// OnUpdate вызывается для каждого полученного обновления: из GetUpdates и из webhook.
+	OnUpdate func(ctx context.Context, update model.Update)
+	// OnUpload вызывается после загрузки файла. size - количество отправленных байт файла.
+	OnUpload func(ctx context.Context, uploadType model.UploadType, size int64, err error)
+	// OnCircuitStateChange вызывается при смене состояния circuit breaker группы запросов.
+	OnCircuitStateChange func(ctx context.Context, group string, from, to CircuitState)
+}
+
+// RequestInfo описывает запрос к API.
+type RequestInfo struct {
+	Method string
+	Path   string
+	// Endpoint путь запроса, в котором идентификаторы заменены на {id}.
+	Endpoint string
+	Query    url.Values
+	ChatID   int64
+}
+
+// ResponseInfo описывает результат запроса к API.
+type ResponseInfo struct {
+	StatusCode int
+	Duration   time.Duration
+	Err        error
+}
+
+var endpointStaticSegments = map[string]struct{}{
+	"me":            {},
+	"answers":       {},
+	"updates":       {},
+	"uploads":       {},
+	"messages":      {},
+	"subscriptions": {},
+	"videos":        {},
+	"chats":         {},
+	"pin":           {},
+	"actions":       {},
+	"members":       {},
+	"admins":        {},
+}
+
+func WithHook(h Hook) Opt {
+	return func(c *client) error {
+		c.hooks = append(c.hooks, h)
+
+		return nil
+	}
+}
+
+func newRequestInfo(method, path string, query url.Values) RequestInfo {
+	info := RequestInfo{
+		Method:   method,
+		Path:     path,
+		Endpoint: endpointName(path),
+		Query:    query,
+	}
+
+	if chatID := query.Get(paramChatID); chatID != "" {
+		info.ChatID, _ = strconv.ParseInt(chatID, 10, 64)
+	}
+
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	if info.ChatID == 0 && len(segments) > 1 && segments[0] == "chats" {
+		info.ChatID, _ = strconv.ParseInt(segments[1], 10, 64)
+	}
+
+	return info
+}
+
+func endpointName(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i, s := range segments {
+		if _, ok := endpointStaticSegments[s]; !ok {
+			segments[i] = "{id}"
+		}
+	}
+
+	return "/" + strings.Join(segments, "/")
+}
+
+func (c *client) onRequest(ctx context.Context, req RequestInfo) context.Context {
+	for _, h := range c.hooks {
+		if h.OnRequest != nil {
+			ctx = h.OnRequest(ctx, req)
+		}
+	}
+
+	return ctx
+}
+
+func (c *client) onResponse(ctx context.Context, req RequestInfo, res ResponseInfo) {
+	for _, h := range c.hooks {
+		if h.OnResponse != nil {
+			h.OnResponse(ctx, req, res)
+		}
+	}
+}
+
+func (c *client) onRetry(ctx context.Context, req RequestInfo, attempt int, err error) {
+	for _, h := range c.hooks {
+		if h.OnRetry != nil {
+			h.OnRetry(ctx, req, attempt, err)
+		}
+	}
+}
+
+func (c *client) onUpdate(ctx context.Context, update model.Update) {
+	for _, h := range c.hooks {
+		if h.OnUpdate != nil {
+			h.OnUpdate(ctx, update)
+		}
+	}
+}
+
+func (c *client) onUpload(ctx context.Context, uploadType model.UploadType, size int64, err error) {
+	for _, h := range c.hooks {
+		if h.OnUpload != nil {
+			h.OnUpload(ctx, uploadType, size, err)
+		}
+	}
+}
+
+func (c *client) onCircuitStateChange(ctx context.Context, group string, from, to CircuitState) {
+	for _, h := range c.hooks {
+		if h.OnCircuitStateChange != nil {
+			h.OnCircuitStateChange(ctx, group, from, to)
+		}
+	}
+}
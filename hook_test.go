@@ -0,0 +1,142 @@
+package maxbot
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+type hookCtxKey struct{}
+
+func TestEndpointName(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{pathMe, "/me"},
+		{pathMessages, "/messages"},
+		{"/messages/mid.abc", "/messages/{id}"},
+		{"/chats/-70000000000005/members/admins/123", "/chats/{id}/members/admins/{id}"},
+		{"/chats/1/pin", "/chats/{id}/pin"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expected, endpointName(tt.path))
+		})
+	}
+}
+
+func TestNewRequestInfo_ChatID(t *testing.T) {
+	info := newRequestInfo(http.MethodGet, "/chats/-42/members", nil)
+	assert.Equal(t, int64(-42), info.ChatID)
+
+	values := url.Values{}
+	values.Set(paramChatID, "100")
+	info = newRequestInfo(http.MethodPost, pathMessages, values)
+	assert.Equal(t, int64(100), info.ChatID)
+}
+
+func TestHook_RequestResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"success":true}`))
+	}))
+	defer srv.Close()
+
+	var (
+		requests  []RequestInfo
+		responses []ResponseInfo
+	)
+	hook := Hook{
+		OnRequest: func(ctx context.Context, req RequestInfo) context.Context {
+			requests = append(requests, req)
+
+			return context.WithValue(ctx, hookCtxKey{}, "request")
+		},
+		OnResponse: func(ctx context.Context, req RequestInfo, res ResponseInfo) {
+			assert.Equal(t, "request", ctx.Value(hookCtxKey{}))
+			responses = append(responses, res)
+		},
+	}
+
+	api, err := NewApi(testToken, WithBaseURL(srv.URL), WithHook(hook))
+	require.NoError(t, err)
+
+	_, err = api.Chats.DeleteChat(context.Background(), 5)
+	require.NoError(t, err)
+
+	require.Len(t, requests, 1)
+	assert.Equal(t, http.MethodDelete, requests[0].Method)
+	assert.Equal(t, "/chats/{id}", requests[0].Endpoint)
+	assert.Equal(t, int64(5), requests[0].ChatID)
+
+	require.Len(t, responses, 1)
+	assert.Equal(t, http.StatusOK, responses[0].StatusCode)
+	assert.NoError(t, responses[0].Err)
+}
+
+func TestHook_OnUpdate(t *testing.T) {
+	data, err := stabs.ReadFile("stabs/update.message_created.json")
+	require.NoError(t, err)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write(data)
+	}))
+	defer srv.Close()
+
+	var received []model.UpdateType
+	hook := Hook{
+		OnUpdate: func(ctx context.Context, update model.Update) {
+			received = append(received, update.UpdateType)
+		},
+	}
+
+	api, err := NewApi(testToken, WithBaseURL(srv.URL), WithHook(hook))
+	require.NoError(t, err)
+
+	_, _, err = api.Subscriptions.GetUpdates(context.Background(), 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, []model.UpdateType{model.UpdateMessageCreated}, received)
+}
+
+func TestHook_OnUpload(t *testing.T) {
+	cli := newClient(testToken, "api.example.com")
+	cli.httpClient = &mockHttpClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == pathUpload {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"url":"https://upload.example.com/upload","token":"video_token"}`)),
+				}, nil
+			}
+			_, _ = io.Copy(io.Discard, req.Body)
+
+			return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader(``))}, nil
+		},
+	}
+
+	var uploaded int64
+	cli.hooks = append(cli.hooks, Hook{
+		OnUpload: func(ctx context.Context, uploadType model.UploadType, size int64, err error) {
+			assert.Equal(t, model.UploadVideo, uploadType)
+			assert.NoError(t, err)
+			uploaded += size
+		},
+	})
+
+	_, err := newUpload(cli).Upload(context.Background(), model.UploadVideo, strings.NewReader("video"), "v.mp4", 5)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), uploaded)
+}
@@ -0,0 +1,340 @@
+// Package i18n переводит тексты бота на язык пользователя из model.Update.UserLocale.
+//
+// Каталоги сообщений загружаются из файлов <locale>.json, <locale>.yaml или <locale>.yml.
+// Вложенные объекты задают составные ключи. Объект, все ключи которого - формы множественного
+// числа CLDR (zero, one, two, few, many, other), задаёт формы, выбираемые по аргументу count;
+// если нужной формы нет, используется other, затем many. В русском для дробных чисел без other
+// берётся few. Параметры подставляются вместо {name}:
+//
+//	{"cart": {"items": {"one": "{count} товар", "few": "{count} товара", "many": "{count} товаров"}}}
+//
+//	bundle, err := i18n.Load(os.DirFS("locales"))
+//	handler := bundle.Middleware(func(ctx context.Context, update model.Update) {
+//		text := i18n.T(ctx, "cart.items", i18n.Args{"count": 3})
+//	})
+package i18n
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	maxbot "github.com/max-messenger/max-bot-api-client-go/v2"
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+const (
+	defaultLocale = "ru"
+	countArg      = "count"
+)
+
+// Args параметры сообщения. Значение count выбирает форму множественного числа.
+type Args map[string]any
+
+type Bundle struct {
+	defaultLocale string
+	// catalogs сообщения по языку и ключу
+	catalogs map[string]map[string]entry
+}
+
+type entry struct {
+	text   string
+	plural map[string]string
+}
+
+type Option func(b *Bundle)
+
+// WithDefaultLocale задаёт язык, который используется, если перевода на язык пользователя нет.
+func WithDefaultLocale(locale string) Option {
+	return func(b *Bundle) {
+		b.defaultLocale = NormalizeLocale(locale)
+	}
+}
+
+func New(opts ...Option) *Bundle {
+	b := &Bundle{
+		defaultLocale: defaultLocale,
+		catalogs:      map[string]map[string]entry{},
+	}
+	for _, opt := range opts {
+		opt(b)
+	}
+
+	return b
+}
+
+// Load загружает каталоги из всех файлов *.json, *.yaml и *.yml в fsys. Язык определяется по имени файла.
+func Load(fsys fs.FS, opts ...Option) (*Bundle, error) {
+	b := New(opts...)
+
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+
+		ext := path.Ext(p)
+		if ext != ".json" && ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return fmt.Errorf("read catalog %s: %w", p, err)
+		}
+
+		var messages map[string]any
+		if ext == ".json" {
+			err = json.Unmarshal(data, &messages)
+		} else {
+			err = yaml.Unmarshal(data, &messages)
+		}
+		if err != nil {
+			return fmt.Errorf("parse catalog %s: %w", p, err)
+		}
+
+		if err = b.AddMessages(strings.TrimSuffix(path.Base(p), ext), messages); err != nil {
+			return fmt.Errorf("load catalog %s: %w", p, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+// AddMessages добавляет сообщения в каталог языка locale. Формат messages тот же, что у файлов каталога.
+func (b *Bundle) AddMessages(locale string, messages map[string]any) error {
+	locale = NormalizeLocale(locale)
+
+	catalog, ok := b.catalogs[locale]
+	if !ok {
+		catalog = map[string]entry{}
+		b.catalogs[locale] = catalog
+	}
+
+	return addMessages(catalog, "", messages)
+}
+
+func addMessages(catalog map[string]entry, prefix string, messages map[string]any) error {
+	for key, value := range messages {
+		key = prefix + key
+
+		switch v := value.(type) {
+		case string:
+			catalog[key] = entry{text: v}
+		case map[string]any:
+			if forms, ok := pluralForms(v); ok {
+				catalog[key] = entry{text: defaultPluralForm(forms), plural: forms}
+
+				continue
+			}
+			if err := addMessages(catalog, key+".", v); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("message %q: unsupported value %T", key, value)
+		}
+	}
+
+	return nil
+}
+
+// pluralForms проверяет, что все ключи m - формы множественного числа.
+func pluralForms(m map[string]any) (map[string]string, bool) {
+	if len(m) == 0 {
+		return nil, false
+	}
+
+	forms := make(map[string]string, len(m))
+	for key, value := range m {
+		text, ok := value.(string)
+		if !ok {
+			return nil, false
+		}
+
+		switch key {
+		case PluralZero, PluralOne, PluralTwo, PluralFew, PluralMany, PluralOther:
+			forms[key] = text
+		default:
+			return nil, false
+		}
+	}
+
+	return forms, true
+}
+
+// defaultPluralForm возвращает форму для count, которого нет среди форм: other, many или любую заданную.
+func defaultPluralForm(forms map[string]string) string {
+	for _, key := range []string{PluralOther, PluralMany, PluralFew, PluralOne, PluralTwo, PluralZero} {
+		if text, ok := forms[key]; ok {
+			return text
+		}
+	}
+
+	return ""
+}
+
+// Localizer переводит сообщения на один язык.
+type Localizer struct {
+	bundle *Bundle
+	locale string
+	// locales порядок поиска перевода
+	locales []string
+}
+
+// Localizer возвращает переводчик для языка locale, например ru-RU. Если перевода нет, ищется
+// перевод для основного языка (ru), затем для языка по умолчанию.
+func (b *Bundle) Localizer(locale string) *Localizer {
+	locales := FallbackLocales(locale, b.defaultLocale)
+
+	return &Localizer{bundle: b, locale: locales[0], locales: locales}
+}
+
+// Locale возвращает язык пользователя.
+func (l *Localizer) Locale() string {
+	return l.locale
+}
+
+// T возвращает перевод key с подставленными args. Если перевода нет, возвращает key.
+func (l *Localizer) T(key string, args ...Args) string {
+	params := Args{}
+	for _, a := range args {
+		for k, v := range a {
+			params[k] = v
+		}
+	}
+
+	for _, locale := range l.locales {
+		e, ok := l.bundle.catalogs[locale][key]
+		if !ok {
+			continue
+		}
+
+		text := e.text
+		if n, ok := toNumber(params[countArg]); ok && e.plural != nil {
+			category := pluralRule(locale)(n)
+			form, ok := e.plural[category]
+			if !ok {
+				form, ok = e.plural[pluralFallback(locale, category)]
+			}
+			if ok {
+				text = form
+			}
+		}
+
+		return format(text, params)
+	}
+
+	return key
+}
+
+// Keyboard возвращает копию клавиатуры, в которой текст кнопок считается ключом и переводится.
+func (l *Localizer) Keyboard(keyboard *model.Keyboard) *model.Keyboard {
+	localized := model.NewKeyboard()
+	if keyboard == nil {
+		return localized
+	}
+
+	for _, buttons := range keyboard.Build().Payload.Buttons {
+		row := localized.AddRow()
+		for _, btn := range buttons {
+			if btn == nil {
+				continue
+			}
+
+			b := *btn
+			b.Text = l.T(b.Text)
+			row.AddButton(b)
+		}
+	}
+
+	return localized
+}
+
+func format(text string, params Args) string {
+	if len(params) == 0 || !strings.Contains(text, "{") {
+		return text
+	}
+
+	pairs := make([]string, 0, len(params)*2)
+	for k, v := range params {
+		pairs = append(pairs, "{"+k+"}", fmt.Sprint(v))
+	}
+
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+func toNumber(v any) (float64, bool) {
+	switch n := v.(type) {
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case string:
+		f, err := strconv.ParseFloat(n, 64)
+
+		return f, err == nil
+	}
+
+	return 0, false
+}
+
+type localizerKey struct{}
+
+// WithLocalizer добавляет переводчик в контекст.
+func WithLocalizer(ctx context.Context, l *Localizer) context.Context {
+	return context.WithValue(ctx, localizerKey{}, l)
+}
+
+// FromContext возвращает переводчик из контекста или nil.
+func FromContext(ctx context.Context) *Localizer {
+	l, _ := ctx.Value(localizerKey{}).(*Localizer)
+
+	return l
+}
+
+// T переводит key на язык из контекста. Без переводчика в контексте возвращает key.
+func T(ctx context.Context, key string, args ...Args) string {
+	l := FromContext(ctx)
+	if l == nil {
+		return key
+	}
+
+	return l.T(key, args...)
+}
+
+// Middleware добавляет в контекст обработчика переводчик на язык update.UserLocale.
+func (b *Bundle) Middleware(next maxbot.UpdateHandler) maxbot.UpdateHandler {
+	return func(ctx context.Context, update model.Update) {
+		next(WithLocalizer(ctx, b.Localizer(update.UserLocale)), update)
+	}
+}
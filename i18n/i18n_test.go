@@ -0,0 +1,135 @@
+package i18n
+
+import (
+	"context"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"locales/ru.json": {Data: []byte(`{
+			"hello": "Привет, {name}!",
+			"menu": {"start": "Начать", "help": "Помощь"},
+			"cart": {"items": {"one": "{count} товар", "few": "{count} товара", "many": "{count} товаров", "other": "{count} товара"}}
+		}`)},
+		"locales/en.yaml": {Data: []byte(`
+hello: "Hello, {name}!"
+menu:
+  start: Start
+cart:
+  items:
+    one: "{count} item"
+    other: "{count} items"
+`)},
+		"locales/en-gb.yml": {Data: []byte(`menu: {start: "Begin"}`)},
+		"locales/README.md": {Data: []byte(`not a catalog`)},
+	}
+}
+
+func TestBundle_T(t *testing.T) {
+	b, err := Load(testFS())
+	require.NoError(t, err)
+
+	tests := []struct {
+		locale   string
+		key      string
+		args     Args
+		expected string
+	}{
+		{"ru", "hello", Args{"name": "Иван"}, "Привет, Иван!"},
+		{"en", "hello", Args{"name": "Ann"}, "Hello, Ann!"},
+		{"en-GB", "menu.start", nil, "Begin"},
+		{"en_GB", "hello", Args{"name": "Ann"}, "Hello, Ann!"},
+		{"en-US", "menu.help", nil, "Помощь"},
+		{"de", "menu.start", nil, "Начать"},
+		{"ru", "missing.key", nil, "missing.key"},
+		{"ru", "cart.items", Args{"count": 1}, "1 товар"},
+		{"ru", "cart.items", Args{"count": 3}, "3 товара"},
+		{"ru", "cart.items", Args{"count": 11}, "11 товаров"},
+		{"ru", "cart.items", Args{"count": 21}, "21 товар"},
+		{"ru", "cart.items", Args{"count": 112}, "112 товаров"},
+		{"ru", "cart.items", Args{"count": 1.5}, "1.5 товара"},
+		{"en", "cart.items", Args{"count": int64(1)}, "1 item"},
+		{"en", "cart.items", Args{"count": 0}, "0 items"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale+"/"+tt.key, func(t *testing.T) {
+			assert.Equal(t, tt.expected, b.Localizer(tt.locale).T(tt.key, tt.args))
+		})
+	}
+}
+
+func TestBundle_Middleware(t *testing.T) {
+	b, err := Load(testFS(), WithDefaultLocale("en"))
+	require.NoError(t, err)
+
+	var got []string
+	handler := b.Middleware(func(ctx context.Context, update model.Update) {
+		got = append(got, T(ctx, "hello", Args{"name": "Bob"}), FromContext(ctx).Locale())
+	})
+
+	handler(context.Background(), model.Update{UserLocale: "ru"})
+	handler(context.Background(), model.Update{})
+
+	assert.Equal(t, []string{"Привет, Bob!", "ru", "Hello, Bob!", "en"}, got)
+	assert.Equal(t, "hello", T(context.Background(), "hello"))
+}
+
+func TestLocalizer_Keyboard(t *testing.T) {
+	b, err := Load(testFS())
+	require.NoError(t, err)
+
+	keyboard := model.NewKeyboard()
+	keyboard.AddRow().AddCallBack("menu.start", "start").AddLink("menu.help", "https://dev.max.ru")
+	keyboard.AddRow().AddMessage("Не ключ")
+
+	buttons := b.Localizer("en").Keyboard(keyboard).Build().Payload.Buttons
+	require.Len(t, buttons, 2)
+	assert.Equal(t, "Start", buttons[0][0].Text)
+	assert.Equal(t, "start", buttons[0][0].Payload)
+	assert.Equal(t, "Помощь", buttons[0][1].Text)
+	assert.Equal(t, "Не ключ", buttons[1][0].Text)
+
+	// исходная клавиатура не меняется
+	assert.Equal(t, "menu.start", keyboard.Build().Payload.Buttons[0][0].Text)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	_, err := Load(fstest.MapFS{"ru.json": {Data: []byte(`{`)}})
+	assert.ErrorContains(t, err, "parse catalog ru.json")
+
+	_, err = Load(fstest.MapFS{"ru.json": {Data: []byte(`{"n": 1}`)}})
+	assert.ErrorContains(t, err, `message "n": unsupported value`)
+}
+
+func TestBundle_PluralWithoutOther(t *testing.T) {
+	b, err := Load(fstest.MapFS{
+		"ru.json": {Data: []byte(`{"cart": {"items": {"one": "{count} товар", "few": "{count} товара", "many": "{count} товаров"}}}`)},
+		"en.json": {Data: []byte(`{"cart": {"items": {"one": "{count} item", "many": "{count} items"}}}`)},
+	})
+	require.NoError(t, err)
+
+	ru := b.Localizer("ru")
+	assert.Equal(t, "1 товар", ru.T("cart.items", Args{"count": 1}))
+	assert.Equal(t, "3 товара", ru.T("cart.items", Args{"count": 3}))
+	assert.Equal(t, "5 товаров", ru.T("cart.items", Args{"count": 5}))
+	assert.Equal(t, "1.5 товара", ru.T("cart.items", Args{"count": 1.5}))
+	assert.Equal(t, "{count} товаров", ru.T("cart.items"))
+	assert.Equal(t, "cart.items.one", ru.T("cart.items.one"))
+
+	// для английского count = 2 даёт other, которой нет в каталоге
+	assert.Equal(t, "2 items", b.Localizer("en").T("cart.items", Args{"count": 2}))
+}
+
+func TestFallbackLocales(t *testing.T) {
+	assert.Equal(t, []string{"ru-ru", "ru", "en"}, FallbackLocales(" ru_RU ", "en"))
+	assert.Equal(t, []string{"en", "ru"}, FallbackLocales("EN", "ru"))
+	assert.Equal(t, []string{"ru"}, FallbackLocales("", "ru"))
+}
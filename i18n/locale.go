@@ -0,0 +1,24 @@
+package i18n
+
+import "strings"
+
+// NormalizeLocale приводит код языка к виду ru-ru: нижний регистр, дефис вместо подчёркивания.
+func NormalizeLocale(locale string) string {
+	return strings.ToLower(strings.ReplaceAll(strings.TrimSpace(locale), "_", "-"))
+}
+
+// FallbackLocales возвращает порядок поиска перевода для locale: ru-ru, ru, затем defaultLocale.
+// Пустой locale даёт только defaultLocale.
+func FallbackLocales(locale, defaultLocale string) []string {
+	locale = NormalizeLocale(locale)
+
+	var locales []string
+	if locale != "" {
+		locales = append(locales, locale)
+		if base, _, ok := strings.Cut(locale, "-"); ok {
+			locales = append(locales, base)
+		}
+	}
+
+	return append(locales, defaultLocale)
+}
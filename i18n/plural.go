@@ -0,0 +1,73 @@
+package i18n
+
+import "strings"
+
+// Формы множественного числа, как в CLDR.
+const (
+	PluralZero  = "zero"
+	PluralOne   = "one"
+	PluralTwo   = "two"
+	PluralFew   = "few"
+	PluralMany  = "many"
+	PluralOther = "other"
+)
+
+// PluralRule выбирает форму множественного числа для n.
+type PluralRule func(n float64) string
+
+var pluralRules = map[string]PluralRule{
+	"ru": russianPlural,
+	"en": englishPlural,
+}
+
+// pluralRule возвращает правило для языка locale, для неизвестных языков - правило английского.
+func pluralRule(locale string) PluralRule {
+	base, _, _ := strings.Cut(locale, "-")
+	if rule, ok := pluralRules[base]; ok {
+		return rule
+	}
+
+	return englishPlural
+}
+
+// pluralFallbacks замена формы, которой нет в каталоге. Дробные числа в русском относятся к other,
+// но в каталогах обычно заданы только one, few и many: 1.5 яблока.
+var pluralFallbacks = map[string]map[string]string{
+	"ru": {PluralOther: PluralFew},
+}
+
+// pluralFallback возвращает замену формы form для языка locale или пустую строку.
+func pluralFallback(locale, form string) string {
+	base, _, _ := strings.Cut(locale, "-")
+
+	return pluralFallbacks[base][form]
+}
+
+func englishPlural(n float64) string {
+	if n == 1 {
+		return PluralOne
+	}
+
+	return PluralOther
+}
+
+// russianPlural: 1 яблоко, 2 яблока, 5 яблок, 1.5 яблока.
+func russianPlural(n float64) string {
+	if n != float64(int64(n)) {
+		return PluralOther
+	}
+
+	i := int64(n)
+	if i < 0 {
+		i = -i
+	}
+
+	switch mod10, mod100 := i%10, i%100; {
+	case mod10 == 1 && mod100 != 11:
+		return PluralOne
+	case mod10 >= 2 && mod10 <= 4 && (mod100 < 12 || mod100 > 14):
+		return PluralFew
+	default:
+		return PluralMany
+	}
+}
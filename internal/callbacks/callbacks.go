@@ -0,0 +1,91 @@
+// Package callbacks разбирает нажатия на кнопки виджетов pagination и menu и отвечает на них.
+//
+// Payload кнопки виджета имеет вид <kind>:<id>:<value>, где kind - тип виджета, id - имя
+// конкретного списка или меню, value - состояние, которое разбирает сам виджет.
+package callbacks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	maxbot "github.com/max-messenger/max-bot-api-client-go/v2"
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+// Handler обрабатывает нажатие с value из payload. nil без ошибки только подтверждает нажатие.
+type Handler func(ctx context.Context, update model.Update, value string) (*maxbot.CallbackAnswer, error)
+
+// ErrorHandler получает ошибки обработки нажатий.
+type ErrorHandler func(ctx context.Context, update model.Update, err error)
+
+type Router struct {
+	messages maxbot.CallbackAnswerer
+	prefix   string
+	handle   Handler
+
+	// ErrorText уведомление, которое видит пользователь, если Handler вернул ошибку. Пустой текст -
+	// нажатие только подтверждается.
+	ErrorText string
+	// OnError получает ошибки Handler и ответа на нажатие. nil - ошибки отбрасываются.
+	OnError ErrorHandler
+}
+
+// New создаёт Router для нажатий с payload <kind>:<id>:... id не может быть пустым и содержать ':'.
+func New(messages maxbot.CallbackAnswerer, kind, id string, handle Handler) (*Router, error) {
+	if id == "" || strings.Contains(id, ":") {
+		return nil, fmt.Errorf("invalid id %q", id)
+	}
+
+	return &Router{
+		messages: messages,
+		prefix:   kind + ":" + id + ":",
+		handle:   handle,
+	}, nil
+}
+
+// Payload возвращает payload кнопки со значением value.
+func (r *Router) Payload(value string) string {
+	return r.prefix + value
+}
+
+// Middleware обрабатывает нажатия на кнопки с префиксом Router. Каждое такое нажатие получает ответ,
+// даже если Handler вернул ошибку. Остальные обновления передаются next.
+func (r *Router) Middleware(next maxbot.UpdateHandler) maxbot.UpdateHandler {
+	return func(ctx context.Context, update model.Update) {
+		if update.Callback == nil {
+			next(ctx, update)
+
+			return
+		}
+
+		value, ok := strings.CutPrefix(update.Callback.Payload, r.prefix)
+		if !ok {
+			next(ctx, update)
+
+			return
+		}
+
+		if err := r.answer(ctx, update, value); err != nil && r.OnError != nil {
+			r.OnError(ctx, update, err)
+		}
+	}
+}
+
+func (r *Router) answer(ctx context.Context, update model.Update, value string) error {
+	answer, err := r.handle(ctx, update, value)
+	switch {
+	case err != nil && r.ErrorText != "":
+		answer = maxbot.NewCallbackAnswer().SetNotification(r.ErrorText)
+	case err != nil || answer == nil:
+		// без ответа кнопка у пользователя остаётся в состоянии загрузки
+		answer = maxbot.NewCallbackAck()
+	}
+
+	if aErr := r.messages.AnswerCallback(ctx, update.Callback.CallbackID, answer); aErr != nil {
+		err = errors.Join(err, aErr)
+	}
+
+	return err
+}
@@ -0,0 +1,107 @@
+package callbacks
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	maxbot "github.com/max-messenger/max-bot-api-client-go/v2"
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+func TestNew_InvalidID(t *testing.T) {
+	for _, id := range []string{"", "a:b"} {
+		_, err := New(nil, "w", id, nil)
+		assert.Error(t, err)
+	}
+}
+
+func TestRouter_Middleware(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(data))
+		if r.URL.Query().Get("callback_id") == "expired" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":"not.found","message":"callback expired"}`))
+
+			return
+		}
+		_, _ = w.Write([]byte(`{"success":true}`))
+	}))
+	defer srv.Close()
+
+	api, err := maxbot.NewApi("token", maxbot.WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	router, err := New(api.ExtendedMessages(), "w", "id", func(_ context.Context, _ model.Update, value string) (*maxbot.CallbackAnswer, error) {
+		switch value {
+		case "fail":
+			return nil, errors.New("failed")
+		case "toast":
+			return maxbot.NewCallbackAnswer().SetNotification("ok"), nil
+		}
+
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	var errs []error
+	router.OnError = func(_ context.Context, _ model.Update, err error) { errs = append(errs, err) }
+
+	var passed int
+	handler := router.Middleware(func(context.Context, model.Update) { passed++ })
+	press := func(id, payload string) {
+		handler(context.Background(), model.Update{Callback: &model.Callback{CallbackID: id, Payload: payload}})
+	}
+
+	press("cb", router.Payload("toast"))
+	press("cb", router.Payload("ack"))
+	press("cb", router.Payload("fail"))
+	router.ErrorText = "error"
+	press("cb", router.Payload("fail"))
+	press("expired", router.Payload("ack"))
+	press("cb", "w:other:ack")
+	handler(context.Background(), model.Update{})
+
+	assert.Equal(t, []string{`{"notification":"ok"}`, `{}`, `{}`, `{"notification":"error"}`, `{}`}, bodies)
+	assert.Equal(t, 2, passed)
+	require.Len(t, errs, 3)
+	assert.ErrorContains(t, errs[0], "failed")
+	assert.ErrorContains(t, errs[2], "callback expired")
+
+	// без OnError ошибки отбрасываются, ответ всё равно отправляется
+	router.OnError = nil
+	press("cb", router.Payload("fail"))
+	assert.Len(t, bodies, 6)
+	assert.Len(t, errs, 3)
+}
+
+type answererFunc func(ctx context.Context, callbackID string, answer *maxbot.CallbackAnswer) error
+
+func (f answererFunc) AnswerCallback(ctx context.Context, callbackID string, answer *maxbot.CallbackAnswer) error {
+	return f(ctx, callbackID, answer)
+}
+
+func TestRouter_Answerer(t *testing.T) {
+	var answered []string
+	answerer := answererFunc(func(_ context.Context, callbackID string, _ *maxbot.CallbackAnswer) error {
+		answered = append(answered, callbackID)
+
+		return nil
+	})
+
+	router, err := New(answerer, "w", "id", func(context.Context, model.Update, string) (*maxbot.CallbackAnswer, error) {
+		return nil, nil
+	})
+	require.NoError(t, err)
+
+	router.Middleware(nil)(context.Background(), model.Update{Callback: &model.Callback{CallbackID: "cb", Payload: router.Payload("x")}})
+	assert.Equal(t, []string{"cb"}, answered)
+}
@@ -0,0 +1,236 @@
+// Package menu строит многоуровневые меню на inline-клавиатуре.
+//
+// Меню задаётся деревом Node. Узел с вложенными узлами открывается как подменю: сообщение заменяется
+// текстом узла и кнопками вложенных узлов, кнопки «назад» и «в начало» добавляются автоматически.
+// Нажатие на лист вызывает его Handler. Payload кнопок меню имеет вид mn:<menu id>:<node id>.
+// На каждое нажатие отправляется ответ: если Handler вернул ошибку, пользователь видит уведомление
+// WithErrorText, чтобы кнопка не оставалась в состоянии загрузки.
+//
+//	settings, err := menu.New(api.ExtendedMessages(), "settings", &menu.Node{
+//		ID: "root", Text: "Настройки",
+//		Children: []*menu.Node{
+//			{ID: "notify", Title: "Уведомления", Text: "Как часто присылать уведомления?", Children: []*menu.Node{
+//				{ID: "daily", Title: "Раз в день", Handler: setFrequency},
+//			}},
+//		},
+//	})
+//	_, err = api.Messages.Send(ctx, settings.Message().SetChat(chatID))
+//	handler = settings.Middleware(handler)
+package menu
+
+import (
+	"context"
+	"fmt"
+
+	maxbot "github.com/max-messenger/max-bot-api-client-go/v2"
+	"github.com/max-messenger/max-bot-api-client-go/v2/internal/callbacks"
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+const (
+	kind           = "mn"
+	defaultColumns = 1
+)
+
+// Handler обрабатывает нажатие на лист меню. Возвращённый ответ отправляется на нажатие,
+// nil подтверждает нажатие без изменения сообщения, ошибка - уведомлением WithErrorText.
+type Handler func(ctx context.Context, update model.Update) (*maxbot.CallbackAnswer, error)
+
+// Node узел меню. ID уникален в пределах меню. Title - надпись кнопки, Text - текст сообщения подменю.
+// Узел содержит либо Children, либо Handler.
+type Node struct {
+	ID       string
+	Title    string
+	Text     string
+	Format   model.TextFormat
+	Children []*Node
+	Handler  Handler
+}
+
+type Menu struct {
+	router    *callbacks.Router
+	id        string
+	root      *Node
+	nodes     map[string]*Node
+	parents   map[string]*Node
+	columns   int
+	back      string
+	home      string
+	errorText string
+	onError   callbacks.ErrorHandler
+}
+
+type Option func(m *Menu)
+
+// WithColumns задаёт число кнопок узлов в ряду.
+func WithColumns(columns int) Option {
+	return func(m *Menu) {
+		m.columns = columns
+	}
+}
+
+// WithLabels задаёт надписи кнопок «назад» и «в начало».
+func WithLabels(back, home string) Option {
+	return func(m *Menu) {
+		m.back, m.home = back, home
+	}
+}
+
+// WithErrorText задаёт уведомление, которое показывается вместо результата листа, вернувшего ошибку.
+func WithErrorText(text string) Option {
+	return func(m *Menu) {
+		m.errorText = text
+	}
+}
+
+// WithErrorHandler получает ошибки Handler листьев и ошибки ответа на нажатие.
+// Без него ошибки отбрасываются.
+func WithErrorHandler(onError func(ctx context.Context, update model.Update, err error)) Option {
+	return func(m *Menu) {
+		m.onError = onError
+	}
+}
+
+// New проверяет дерево root и создаёт меню. id входит в payload кнопок, поэтому у меню,
+// которые обрабатываются одной цепочкой Middleware, id должны различаться.
+func New(messages maxbot.CallbackAnswerer, id string, root *Node, opts ...Option) (*Menu, error) {
+	m := &Menu{
+		id:        id,
+		root:      root,
+		nodes:     map[string]*Node{},
+		parents:   map[string]*Node{},
+		columns:   defaultColumns,
+		back:      "« Назад",
+		home:      "В начало",
+		errorText: "Не удалось выполнить действие",
+	}
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	router, err := callbacks.New(messages, kind, id, m.handle)
+	if err != nil {
+		return nil, fmt.Errorf("menu: %w", err)
+	}
+	router.ErrorText, router.OnError = m.errorText, m.onError
+	m.router = router
+
+	if m.columns < 1 || m.columns > maxbot.MaxButtonsPerRow {
+		return nil, fmt.Errorf("menu: columns must be between 1 and %d, got %d", maxbot.MaxButtonsPerRow, m.columns)
+	}
+	switch {
+	case root == nil || len(root.Children) == 0:
+		return nil, fmt.Errorf("menu %s: root must have children", id)
+	case root.ID == "":
+		return nil, fmt.Errorf("menu %s: empty root id", id)
+	}
+	if err = m.add(root, nil); err != nil {
+		return nil, fmt.Errorf("menu %s: %w", id, err)
+	}
+
+	return m, nil
+}
+
+func (m *Menu) add(node, parent *Node) error {
+	switch {
+	case node == nil:
+		return fmt.Errorf("nil node in %q", parent.ID)
+	case node.ID == "":
+		return fmt.Errorf("empty node id in %q", parent.ID)
+	case m.nodes[node.ID] != nil:
+		return fmt.Errorf("duplicate node id %q", node.ID)
+	case len(node.Children) > 0 && node.Handler != nil:
+		return fmt.Errorf("node %q has both children and handler", node.ID)
+	case len(node.Children) == 0 && node.Handler == nil:
+		return fmt.Errorf("node %q has neither children nor handler", node.ID)
+	case len(node.Children) > (maxbot.MaxKeyboardRows-1)*m.columns:
+		return fmt.Errorf("node %q has too many children", node.ID)
+	}
+
+	m.nodes[node.ID] = node
+	if parent != nil {
+		m.parents[node.ID] = parent
+	}
+
+	for _, child := range node.Children {
+		if err := m.add(child, node); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Message возвращает корневой экран меню. Адресата задаёт вызывающий код: SetChat, SetUser или Api.Reply.
+func (m *Menu) Message() *maxbot.Message {
+	return m.message(m.root)
+}
+
+// Open возвращает сообщение с подменю nodeID.
+func (m *Menu) Open(nodeID string) (*maxbot.Message, error) {
+	node, ok := m.nodes[nodeID]
+	if !ok || len(node.Children) == 0 {
+		return nil, fmt.Errorf("menu %s: unknown submenu %q", m.id, nodeID)
+	}
+
+	return m.message(node), nil
+}
+
+// Keyboard возвращает клавиатуру подменю node: кнопки вложенных узлов и навигации.
+func (m *Menu) Keyboard(node *Node) *model.Keyboard {
+	kb := model.NewKeyboard()
+
+	var row *model.KeyboardRow
+	for i, child := range node.Children {
+		if i%m.columns == 0 {
+			row = kb.AddRow()
+		}
+		row.AddCallBack(child.Title, m.payload(child.ID))
+	}
+
+	if parent, ok := m.parents[node.ID]; ok {
+		nav := kb.AddRow().AddCallBack(m.back, m.payload(parent.ID))
+		// с первого уровня «назад» уже ведёт в начало
+		if parent != m.root {
+			nav.AddCallBack(m.home, m.payload(m.root.ID))
+		}
+	}
+
+	return kb
+}
+
+// Middleware открывает подменю и вызывает Handler листьев по нажатиям на кнопки этого меню.
+// Обновления, не относящиеся к меню, получает next.
+func (m *Menu) Middleware(next maxbot.UpdateHandler) maxbot.UpdateHandler {
+	return m.router.Middleware(next)
+}
+
+func (m *Menu) handle(ctx context.Context, update model.Update, nodeID string) (*maxbot.CallbackAnswer, error) {
+	node, ok := m.nodes[nodeID]
+	if !ok {
+		// меню изменилось после отправки сообщения
+		node = m.root
+	}
+
+	if len(node.Children) > 0 {
+		return maxbot.NewCallbackAnswer().SetMessage(m.message(node)), nil
+	}
+
+	answer, err := node.Handler(ctx, update)
+	if err != nil {
+		return nil, fmt.Errorf("menu %s: node %s: %w", m.id, node.ID, err)
+	}
+
+	return answer, nil
+}
+
+func (m *Menu) message(node *Node) *maxbot.Message {
+	return maxbot.NewMessage().
+		SetText(node.Text).
+		SetFormat(node.Format).
+		AddKeyboard(m.Keyboard(node))
+}
+
+func (m *Menu) payload(nodeID string) string {
+	return m.router.Payload(nodeID)
+}
@@ -0,0 +1,164 @@
+package menu
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	maxbot "github.com/max-messenger/max-bot-api-client-go/v2"
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+func testTree(handler Handler) *Node {
+	return &Node{ID: "root", Text: "Настройки", Children: []*Node{
+		{ID: "notify", Title: "Уведомления", Text: "Уведомления", Children: []*Node{
+			{ID: "freq", Title: "Частота", Text: "Как часто?", Children: []*Node{
+				{ID: "daily", Title: "Раз в день", Handler: handler},
+				{ID: "weekly", Title: "Раз в неделю", Handler: handler},
+			}},
+		}},
+		{ID: "lang", Title: "Язык", Handler: handler},
+	}}
+}
+
+func payloads(body model.NewMessageBody) [][]string {
+	var rows [][]string
+	for _, row := range body.Attachments[0].Payload.Buttons {
+		var payloads []string
+		for _, btn := range row {
+			payloads = append(payloads, btn.Payload)
+		}
+		rows = append(rows, payloads)
+	}
+
+	return rows
+}
+
+func TestMenu_Open(t *testing.T) {
+	m, err := New(nil, "settings", testTree(nil))
+	require.Error(t, err)
+	assert.Nil(t, m)
+
+	noop := func(context.Context, model.Update) (*maxbot.CallbackAnswer, error) { return nil, nil }
+	m, err = New(nil, "settings", testTree(noop))
+	require.NoError(t, err)
+
+	body := m.Message().MessageBody()
+	assert.Equal(t, "Настройки", body.Text)
+	assert.Equal(t, [][]string{{"mn:settings:notify"}, {"mn:settings:lang"}}, payloads(body))
+
+	msg, err := m.Open("notify")
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"mn:settings:freq"}, {"mn:settings:root"}}, payloads(msg.MessageBody()))
+
+	msg, err = m.Open("freq")
+	require.NoError(t, err)
+	assert.Equal(t, "Как часто?", msg.MessageBody().Text)
+	assert.Equal(t, [][]string{
+		{"mn:settings:daily"},
+		{"mn:settings:weekly"},
+		{"mn:settings:notify", "mn:settings:root"},
+	}, payloads(msg.MessageBody()))
+
+	_, err = m.Open("lang")
+	assert.Error(t, err)
+}
+
+func TestNew_Invalid(t *testing.T) {
+	noop := func(context.Context, model.Update) (*maxbot.CallbackAnswer, error) { return nil, nil }
+	leaf := func(id string) *Node { return &Node{ID: id, Title: id, Handler: noop} }
+
+	tests := map[string]*Node{
+		"no children":   {ID: "root"},
+		"duplicate id":  {ID: "root", Children: []*Node{leaf("a"), leaf("a")}},
+		"empty id":      {ID: "root", Children: []*Node{leaf("")}},
+		"empty root id": {Text: "root", Children: []*Node{leaf("a")}},
+		"nil child":     {ID: "root", Children: []*Node{nil}},
+		"both":          {ID: "root", Children: []*Node{{ID: "a", Handler: noop, Children: []*Node{leaf("b")}}}},
+	}
+	for name, root := range tests {
+		t.Run(name, func(t *testing.T) {
+			_, err := New(nil, "m", root)
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := New(nil, "", &Node{ID: "root", Children: []*Node{leaf("a")}})
+	assert.Error(t, err)
+}
+
+func TestMenu_Middleware(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(data))
+		_, _ = w.Write([]byte(`{"success":true}`))
+	}))
+	defer srv.Close()
+
+	api, err := maxbot.NewApi("token", maxbot.WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	var leaves []string
+	var errs []error
+	m, err := New(api.ExtendedMessages(), "settings", testTree(func(_ context.Context, update model.Update) (*maxbot.CallbackAnswer, error) {
+		leaves = append(leaves, update.Callback.Payload)
+		switch update.Callback.Payload {
+		case "mn:settings:lang":
+			return nil, errors.New("not implemented")
+		case "mn:settings:weekly":
+			return nil, nil
+		}
+
+		return maxbot.NewCallbackAnswer().SetNotification("saved"), nil
+	}), WithErrorText("Ошибка"), WithErrorHandler(func(_ context.Context, _ model.Update, err error) {
+		errs = append(errs, err)
+	}))
+	require.NoError(t, err)
+
+	var passed []string
+	handler := m.Middleware(func(_ context.Context, update model.Update) {
+		passed = append(passed, update.GetCallback().Payload)
+	})
+	press := func(payload string) {
+		handler(context.Background(), model.Update{Callback: &model.Callback{CallbackID: "cb", Payload: payload}})
+	}
+
+	press("mn:settings:freq")
+	require.Len(t, bodies, 1)
+	var answer model.CallbackAnswer
+	require.NoError(t, json.Unmarshal([]byte(bodies[0]), &answer))
+	require.NotNil(t, answer.Message)
+	assert.Equal(t, "Как часто?", answer.Message.Text)
+
+	press("mn:settings:daily")
+	require.Len(t, bodies, 2)
+	assert.JSONEq(t, `{"notification":"saved"}`, bodies[1])
+
+	press("mn:settings:weekly")
+	require.Len(t, bodies, 3)
+	assert.JSONEq(t, `{}`, bodies[2])
+
+	// ошибка листа: нажатие подтверждается уведомлением, ошибка передаётся обработчику
+	press("mn:settings:lang")
+	require.Len(t, bodies, 4)
+	assert.JSONEq(t, `{"notification":"Ошибка"}`, bodies[3])
+	require.Len(t, errs, 1)
+	assert.ErrorContains(t, errs[0], "not implemented")
+
+	press("mn:settings:removed")
+	require.Len(t, bodies, 5)
+	require.NoError(t, json.Unmarshal([]byte(bodies[4]), &answer))
+	assert.Equal(t, "Настройки", answer.Message.Text)
+
+	press("other")
+	assert.Equal(t, []string{"mn:settings:daily", "mn:settings:weekly", "mn:settings:lang"}, leaves)
+	assert.Equal(t, []string{"other"}, passed)
+}
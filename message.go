@@ -55,6 +55,17 @@ func (m *Message) SetFormat(format model.TextFormat) *Message {
 	return m
 }
 
+// FormattedText текст с разметкой, например text.Builder.
+type FormattedText interface {
+	String() string
+	Format() model.TextFormat
+}
+
+// SetFormattedText задаёт текст и формат разметки.
+func (m *Message) SetFormattedText(t FormattedText) *Message {
+	return m.SetText(t.String()).SetFormat(t.Format())
+}
+
 // AddSticker добавляет стикер. Сообщение не должно содержать текста и других вложений.
 func (m *Message) AddSticker(stickerCode string) *Message {
 	attach := model.Attachment{
@@ -81,6 +92,29 @@ func (m *Message) AddContact(userID int64) *Message {
 	return m
 }
 
+// AddContactPhone добавляет контакт с именем и номером телефона. Сообщение не должно содержать текста и других вложений.
+func (m *Message) AddContactPhone(name, phone string) *Message {
+	return m.AddVCard(model.VCard{
+		Name:   name,
+		Phones: []model.VCardPhone{{Number: phone, Types: []string{"cell"}}},
+	})
+}
+
+// AddVCard добавляет контакт из vCard. Сообщение не должно содержать текста и других вложений.
+func (m *Message) AddVCard(card model.VCard) *Message {
+	attach := model.Attachment{
+		Type: model.AttachContact,
+		Payload: model.Payload{
+			Name:     card.DisplayName(),
+			VCFInfo:  card.String(),
+			VCFPhone: card.Phone(),
+		},
+	}
+	m.message.Attachments = append(m.message.Attachments, attach)
+
+	return m
+}
+
 func (m *Message) AddLocation(lat, lot float64) *Message {
 	attach := model.Attachment{
 		Type:      model.AttachLocation,
@@ -112,6 +146,13 @@ func (m *Message) WithoutNotify() *Message {
 	return m
 }
 
+// SetForward пересылает сообщение mid. Текст и вложения для пересылки не нужны.
+func (m *Message) SetForward(mid string) *Message {
+	m.message.Link = &model.NewMessageLink{Type: model.LinkTypeForward, Mid: mid}
+
+	return m
+}
+
 func (m *Message) SetReply(text, id string) *Message {
 	m.message.Text = text
 	m.message.Link = &model.NewMessageLink{Type: model.LinkTypeReply, Mid: id}
@@ -143,6 +184,15 @@ func (m *Message) AddAttachByToken(fileToken string, at model.AttachmentType) *M
 	return m
 }
 
+// AddUploads прикрепляет загруженные файлы, например результат UploadBatchResults.Uploaded.
+func (m *Message) AddUploads(results ...model.UploadResult) *Message {
+	for _, res := range results {
+		m.AddAttachByToken(res.Token, res.Type.AttachmentType())
+	}
+
+	return m
+}
+
 func (m *Message) MessageBody() model.NewMessageBody {
 	return m.message
 }
@@ -7,6 +7,7 @@ import (
 	"github.com/stretchr/testify/require"
 
 	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+	"github.com/max-messenger/max-bot-api-client-go/v2/text"
 )
 
 func TestNewMessage(t *testing.T) {
@@ -121,6 +122,44 @@ func TestMessage_AddContact(t *testing.T) {
 	assert.Equal(t, contactID, attach.Payload.ContactID)
 }
 
+func TestMessage_AddContactPhone(t *testing.T) {
+	msg := NewMessage()
+
+	result := msg.AddContactPhone("Ivan Petrov", "+79990000001")
+
+	assert.Equal(t, msg, result)
+	require.Len(t, msg.message.Attachments, 1)
+
+	attach := msg.message.Attachments[0]
+	assert.Equal(t, model.AttachContact, attach.Type)
+	assert.Equal(t, "Ivan Petrov", attach.Payload.Name)
+	assert.Equal(t, "+79990000001", attach.Payload.VCFPhone)
+
+	card, err := model.ParseVCard(attach.Payload.VCFInfo)
+	require.NoError(t, err)
+	assert.Equal(t, "Ivan Petrov", card.Name)
+	assert.Equal(t, "+79990000001", card.Phone())
+}
+
+func TestMessage_SetFormattedText(t *testing.T) {
+	msg := NewMessage()
+
+	result := msg.SetFormattedText(text.HTML().Bold("<b>"))
+
+	assert.Equal(t, msg, result)
+	assert.Equal(t, "<b>&lt;b&gt;</b>", msg.message.Text)
+	assert.Equal(t, model.FormatHTML, msg.message.Format)
+}
+
+func TestMessage_SetForward(t *testing.T) {
+	msg := NewMessage()
+
+	result := msg.SetForward("mid.1")
+
+	assert.Equal(t, msg, result)
+	assert.Equal(t, &model.NewMessageLink{Type: model.LinkTypeForward, Mid: "mid.1"}, msg.message.Link)
+}
+
 func TestMessage_AddLocation(t *testing.T) {
 	msg := NewMessage()
 	lat, lon := 55.751244, 37.618423
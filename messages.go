@@ -2,6 +2,7 @@ package maxbot
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -52,6 +53,11 @@ func (m *Messages) Send(ctx context.Context, msg *Message) (res model.SendMessag
 
 		return
 	}
+	if m.client.validateMessages {
+		if err = msg.Validate(); err != nil {
+			return
+		}
+	}
 	values := url.Values{}
 	if msg.userID > 0 {
 		values.Set(paramUserID, strconv.FormatInt(msg.userID, 10))
@@ -64,9 +70,25 @@ func (m *Messages) Send(ctx context.Context, msg *Message) (res model.SendMessag
 	}
 	err = m.client.rawWithRetry(ctx, http.MethodPost, pathMessages, values, msg.message, &res)
 
+	apiErr := &Error{}
+	if m.client.uploadCache != nil && errors.As(err, &apiErr) && apiErr.IsAttachmentError() {
+		m.client.uploadCache.invalidateMessage(ctx, msg.message)
+	}
+
 	return
 }
 
+// Forward пересылает сообщение mid в чат chatID или пользователю userID. Задан должен быть ровно один адресат.
+func (m *Messages) Forward(ctx context.Context, mid string, chatID, userID int64) (res model.SendMessageResult, err error) {
+	if (chatID == 0) == (userID == 0) {
+		err = fmt.Errorf("forward: exactly one of chat or user must be set")
+
+		return
+	}
+
+	return m.Send(ctx, NewMessage().SetChat(chatID).SetUser(userID).SetForward(mid))
+}
+
 func (m *Messages) EditMessage(ctx context.Context, messageID string, body model.NewMessageBody) (res model.SimpleQueryResult, err error) {
 	values := url.Values{}
 	values.Set(paramMessageID, messageID)
@@ -0,0 +1,60 @@
+package maxbot
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessages_Forward(t *testing.T) {
+	var query, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		query, body = r.URL.RawQuery, string(data)
+		_, _ = w.Write([]byte(`{"message":{"body":{"mid":"mid.2"}}}`))
+	}))
+	defer srv.Close()
+
+	api, err := NewApi(testToken, WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	res, err := api.ExtendedMessages().Forward(context.Background(), "mid.1", 200, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "mid.2", res.Message.Body.Mid)
+	assert.Equal(t, "chat_id=200", query)
+	assert.JSONEq(t, `{"attachments":null,"link":{"type":"forward","mid":"mid.1"}}`, body)
+
+	query = ""
+	_, err = api.ExtendedMessages().Forward(context.Background(), "mid.1", 200, 300)
+	assert.Error(t, err)
+	_, err = api.ExtendedMessages().Forward(context.Background(), "mid.1", 0, 0)
+	assert.Error(t, err)
+	assert.Empty(t, query)
+}
+
+func TestUpdateRaw_FromRaw_Link(t *testing.T) {
+	link := `"link":{"type":"forward","sender":{"user_id":7,"name":"Ann"},"chat_id":300,"message":{"mid":"mid.0","text":"flagged"}}`
+
+	for _, updateType := range []string{"message_created", "message_callback"} {
+		t.Run(updateType, func(t *testing.T) {
+			var raw updateRaw
+			data := `{"update_type":"` + updateType + `","message":{"recipient":{"chat_id":100},"body":{"mid":"mid.1"},` + link + `}}`
+			require.NoError(t, json.Unmarshal([]byte(data), &raw))
+
+			forwarded, ok := raw.FromRaw().GetMessage().Forwarded()
+			require.True(t, ok)
+			assert.Equal(t, int64(300), forwarded.ChatID)
+			assert.Equal(t, "Ann", forwarded.GetSender().Name)
+			assert.Equal(t, "flagged", forwarded.Message.Text)
+
+			_, ok = raw.FromRaw().GetMessage().ReplyTo()
+			assert.False(t, ok)
+		})
+	}
+}
@@ -0,0 +1,146 @@
+// Package metrics собирает метрики клиента и отдаёт их в текстовом формате Prometheus
+// без зависимости от клиентской библиотеки Prometheus.
+//
+//	m := metrics.New()
+//	api, err := maxbot.NewApi(token, maxbot.WithHook(m.Hook()))
+//	http.Handle("/metrics", m.Handler())
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"time"
+
+	maxbot "github.com/max-messenger/max-bot-api-client-go/v2"
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+const (
+	contentType = "text/plain; version=0.0.4; charset=utf-8"
+
+	errorCodeNetwork     = "network"
+	errorCodeCircuitOpen = "circuit_open"
+	errorCodeTimeout     = "timeout"
+	errorCodeUnknown     = "unknown"
+)
+
+type Metrics struct {
+	sentMessages    *metric
+	receivedUpdates *metric
+	apiErrors       *metric
+	retries         *metric
+	uploadBytes     *metric
+	uploads         *metric
+	pollLag         *metric
+
+	now func() time.Time
+}
+
+func New() *Metrics {
+	return &Metrics{
+		sentMessages:    newMetric("maxbot_sent_messages_total", "Number of successfully sent messages.", typeCounter),
+		receivedUpdates: newMetric("maxbot_received_updates_total", "Number of received updates by type.", typeCounter, "update_type"),
+		apiErrors:       newMetric("maxbot_api_errors_total", "Number of failed API requests by error code.", typeCounter, "endpoint", "code"),
+		retries:         newMetric("maxbot_api_retries_total", "Number of retried API requests.", typeCounter, "endpoint"),
+		uploadBytes:     newMetric("maxbot_upload_bytes_total", "Number of uploaded bytes by upload type.", typeCounter, "upload_type"),
+		uploads:         newMetric("maxbot_uploads_total", "Number of uploads by upload type and result.", typeCounter, "upload_type", "result"),
+		pollLag:         newMetric("maxbot_update_lag_seconds", "Delay between update creation and its receipt by the bot.", typeGauge, "update_type"),
+		now:             time.Now,
+	}
+}
+
+// Hook возвращает обработчики для maxbot.WithHook.
+func (m *Metrics) Hook() maxbot.Hook {
+	return maxbot.Hook{
+		OnResponse: m.onResponse,
+		OnRetry:    m.onRetry,
+		OnUpdate:   m.onUpdate,
+		OnUpload:   m.onUpload,
+	}
+}
+
+// Handler возвращает обработчик для endpoint /metrics.
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		buf := &bytes.Buffer{}
+		if err := m.write(buf); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", contentType)
+		_, _ = w.Write(buf.Bytes())
+	})
+}
+
+func (m *Metrics) write(buf *bytes.Buffer) error {
+	for _, mt := range []*metric{m.sentMessages, m.receivedUpdates, m.apiErrors, m.retries, m.uploadBytes, m.uploads, m.pollLag} {
+		if err := mt.write(buf); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *Metrics) onResponse(_ context.Context, req maxbot.RequestInfo, res maxbot.ResponseInfo) {
+	if res.Err != nil {
+		m.apiErrors.add(1, req.Endpoint, errorCode(res.Err))
+
+		return
+	}
+
+	if req.Method == http.MethodPost && req.Endpoint == "/messages" {
+		m.sentMessages.add(1)
+	}
+}
+
+func (m *Metrics) onRetry(_ context.Context, req maxbot.RequestInfo, _ int, _ error) {
+	m.retries.add(1, req.Endpoint)
+}
+
+func (m *Metrics) onUpdate(_ context.Context, update model.Update) {
+	m.receivedUpdates.add(1, string(update.UpdateType))
+
+	if update.Timestamp > 0 {
+		lag := m.now().Sub(update.GetTimestampTime())
+		m.pollLag.set(lag.Seconds(), string(update.UpdateType))
+	}
+}
+
+func (m *Metrics) onUpload(_ context.Context, uploadType model.UploadType, size int64, err error) {
+	if err != nil {
+		m.uploads.add(1, string(uploadType), "error")
+
+		return
+	}
+
+	m.uploads.add(1, string(uploadType), "success")
+	m.uploadBytes.add(float64(size), string(uploadType))
+}
+
+func errorCode(err error) string {
+	var apiErr *maxbot.Error
+	if errors.As(err, &apiErr) && apiErr.Code != "" {
+		return apiErr.Code
+	}
+
+	if errors.Is(err, maxbot.ErrCircuitOpen) {
+		return errorCodeCircuitOpen
+	}
+
+	var timeoutErr *maxbot.TimeoutError
+	if errors.As(err, &timeoutErr) {
+		return errorCodeTimeout
+	}
+
+	var networkErr *maxbot.NetworkError
+	if errors.As(err, &networkErr) {
+		return errorCodeNetwork
+	}
+
+	return errorCodeUnknown
+}
@@ -0,0 +1,90 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	maxbot "github.com/max-messenger/max-bot-api-client-go/v2"
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+func TestMetrics_Client(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/messages":
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"message":{}}`))
+		case "/updates":
+			w.WriteHeader(http.StatusOK)
+			_, _ = w.Write([]byte(`{"updates":[{"update_type":"bot_started","timestamp":1000},{"update_type":"message_created","timestamp":1000}],"marker":1}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":"not.found","message":"not found"}`))
+		}
+	}))
+	defer srv.Close()
+
+	m := New()
+	m.now = func() time.Time { return time.UnixMilli(3500) }
+
+	api, err := maxbot.NewApi("token", maxbot.WithBaseURL(srv.URL), maxbot.WithHook(m.Hook()))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	_, err = api.Messages.Send(ctx, maxbot.NewMessage().SetChat(1).SetText("hello"))
+	require.NoError(t, err)
+
+	_, err = api.Chats.GetChat(ctx, 10)
+	require.Error(t, err)
+
+	_, _, err = api.Subscriptions.GetUpdates(ctx, 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, float64(1), m.sentMessages.value())
+	assert.Equal(t, float64(1), m.apiErrors.value("/chats/{id}", "not.found"))
+	assert.Equal(t, float64(1), m.receivedUpdates.value(string(model.UpdateBotStarted)))
+	assert.Equal(t, float64(1), m.receivedUpdates.value(string(model.UpdateMessageCreated)))
+	assert.Equal(t, 2.5, m.pollLag.value(string(model.UpdateBotStarted)))
+}
+
+func TestMetrics_Upload(t *testing.T) {
+	m := New()
+	hook := m.Hook()
+
+	hook.OnUpload(context.Background(), model.UploadImage, 100, nil)
+	hook.OnUpload(context.Background(), model.UploadImage, 50, nil)
+	hook.OnUpload(context.Background(), model.UploadVideo, 10, io.ErrUnexpectedEOF)
+
+	assert.Equal(t, float64(150), m.uploadBytes.value(string(model.UploadImage)))
+	assert.Equal(t, float64(0), m.uploadBytes.value(string(model.UploadVideo)))
+	assert.Equal(t, float64(1), m.uploads.value(string(model.UploadVideo), "error"))
+}
+
+func TestMetrics_Handler(t *testing.T) {
+	m := New()
+	hook := m.Hook()
+	hook.OnRetry(context.Background(), maxbot.RequestInfo{Endpoint: "/messages"}, 1, nil)
+	hook.OnResponse(context.Background(), maxbot.RequestInfo{Endpoint: "/me"}, maxbot.ResponseInfo{
+		Err: &maxbot.Error{Code: `bad"code`},
+	})
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, contentType, rec.Header().Get("Content-Type"))
+
+	body := rec.Body.String()
+	assert.Contains(t, body, "# TYPE maxbot_api_retries_total counter\n")
+	assert.Contains(t, body, `maxbot_api_retries_total{endpoint="/messages"} 1`+"\n")
+	assert.Contains(t, body, `maxbot_api_errors_total{endpoint="/me",code="bad\"code"} 1`+"\n")
+	assert.True(t, strings.HasPrefix(body, "# HELP maxbot_sent_messages_total"))
+}
@@ -0,0 +1,135 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+const (
+	typeCounter = "counter"
+	typeGauge   = "gauge"
+)
+
+type metric struct {
+	name   string
+	help   string
+	kind   string
+	labels []string
+
+	mu     sync.Mutex
+	values map[string]*sample
+}
+
+type sample struct {
+	labels []string
+	value  float64
+}
+
+func newMetric(name, help, kind string, labels ...string) *metric {
+	return &metric{
+		name:   name,
+		help:   help,
+		kind:   kind,
+		labels: labels,
+		values: make(map[string]*sample),
+	}
+}
+
+func (m *metric) add(value float64, labels ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.get(labels).value += value
+}
+
+func (m *metric) set(value float64, labels ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.get(labels).value = value
+}
+
+func (m *metric) value(labels ...string) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if s, ok := m.values[strings.Join(labels, "\xff")]; ok {
+		return s.value
+	}
+
+	return 0
+}
+
+func (m *metric) get(labels []string) *sample {
+	key := strings.Join(labels, "\xff")
+	s, ok := m.values[key]
+	if !ok {
+		s = &sample{labels: labels}
+		m.values[key] = s
+	}
+
+	return s
+}
+
+// write выводит метрику в текстовом формате Prometheus.
+func (m *metric) write(w io.Writer) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, err := fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s %s\n", m.name, escapeHelp(m.help), m.name, m.kind); err != nil {
+		return err
+	}
+
+	keys := make([]string, 0, len(m.values))
+	for k := range m.values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		s := m.values[k]
+		if _, err := fmt.Fprintf(w, "%s%s %s\n", m.name, m.formatLabels(s.labels), formatValue(s.value)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *metric) formatLabels(values []string) string {
+	if len(m.labels) == 0 {
+		return ""
+	}
+
+	pairs := make([]string, len(m.labels))
+	for i, name := range m.labels {
+		var v string
+		if i < len(values) {
+			v = values[i]
+		}
+		pairs[i] = name + `="` + escapeLabel(v) + `"`
+	}
+
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(v float64) string {
+	return strconv.FormatFloat(v, 'g', -1, 64)
+}
+
+var (
+	helpReplacer  = strings.NewReplacer(`\`, `\\`, "\n", `\n`)
+	labelReplacer = strings.NewReplacer(`\`, `\\`, "\n", `\n`, `"`, `\"`)
+)
+
+func escapeHelp(s string) string {
+	return helpReplacer.Replace(s)
+}
+
+func escapeLabel(s string) string {
+	return labelReplacer.Replace(s)
+}
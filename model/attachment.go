@@ -1,26 +1,49 @@
 package model
 
+// Attachment вложение сообщения. Набор заполненных полей зависит от Type,
+// типизированное представление возвращает Typed.
 type Attachment struct {
 	Type     AttachmentType `json:"type"`
 	Payload  Payload        `json:"payload"`
-	FileName string         `json:"filename"`
-	Size     int            `json:"size"`
+	FileName string         `json:"filename,omitempty"` // file
+	Size     int            `json:"size,omitempty"`     // file
 
-	Latitude  float64 `json:"latitude,omitempty"`
-	Longitude float64 `json:"longitude,omitempty"`
+	Latitude  float64 `json:"latitude,omitempty"`  // location
+	Longitude float64 `json:"longitude,omitempty"` // location
+
+	Width     int             `json:"width,omitempty"`     // video, sticker
+	Height    int             `json:"height,omitempty"`    // video, sticker
+	Duration  int             `json:"duration,omitempty"`  // video
+	Thumbnail *VideoThumbnail `json:"thumbnail,omitempty"` // video
+
+	Transcription string `json:"transcription,omitempty"` // audio
+
+	Title       string `json:"title,omitempty"`       // share
+	Description string `json:"description,omitempty"` // share
+	ImageURL    string `json:"image_url,omitempty"`   // share
+
+	Buttons [][]*Button `json:"buttons,omitempty"` // reply_keyboard
+	Data    string      `json:"data,omitempty"`    // data
 }
 
 type Payload struct {
+	PhotoID   int64       `json:"photo_id,omitempty"`
 	Token     string      `json:"token,omitempty"`
 	URL       string      `json:"url,omitempty"`
 	Code      string      `json:"code,omitempty"`
 	ContactID int64       `json:"contact_id,omitempty"` // for send contact
-	VCFInfo   string      `json:"vcf_info"`
-	MaxInfo   User        `json:"max_info"`
-	Hash      string      `json:"hash"`
+	Name      string      `json:"name,omitempty"`       // for send contact
+	VCFInfo   string      `json:"vcf_info,omitempty"`
+	VCFPhone  string      `json:"vcf_phone,omitempty"` // for send contact
+	MaxInfo   User        `json:"max_info,omitzero"`
+	Hash      string      `json:"hash,omitempty"`
 	Buttons   [][]*Button `json:"buttons,omitempty"`
 }
 
+type VideoThumbnail struct {
+	URL string `json:"url"`
+}
+
 type VideoUrls struct {
 	Mp41080 *string `json:"mp4_1080"`
 	Mp4720  *string `json:"mp4_720"`
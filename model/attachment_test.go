@@ -0,0 +1,78 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestAttachmentTyped(t *testing.T) {
+	data := `[
+		{"type":"image","payload":{"photo_id":42,"token":"img","url":"https://i.example/1.jpg"}},
+		{"type":"video","payload":{"token":"vid","url":"https://v.example/1"},"thumbnail":{"url":"https://v.example/1.jpg"},"width":1280,"height":720,"duration":600},
+		{"type":"audio","payload":{"token":"aud","url":"https://a.example/1"},"transcription":"hello"},
+		{"type":"file","payload":{"token":"doc","url":"https://f.example/1"},"filename":"report.pdf","size":1024},
+		{"type":"sticker","payload":{"code":"smile","url":"https://s.example/1"},"width":128,"height":64},
+		{"type":"contact","payload":{"vcf_info":"BEGIN:VCARD","max_info":{"user_id":7,"first_name":"Ann"}}},
+		{"type":"share","payload":{"url":"https://max.ru"},"title":"MAX","description":"messenger","image_url":"https://max.ru/i.png"},
+		{"type":"location","latitude":55.75,"longitude":37.61},
+		{"type":"reply_keyboard","buttons":[[{"type":"message","text":"Yes"}]]},
+		{"type":"data","data":"payload"},
+		{"type":"unknown"}
+	]`
+
+	var attachments []Attachment
+	if err := json.Unmarshal([]byte(data), &attachments); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []TypedAttachment{
+		PhotoAttachment{PhotoID: 42, Token: "img", URL: "https://i.example/1.jpg"},
+		VideoAttachment{Token: "vid", URL: "https://v.example/1", Thumbnail: &VideoThumbnail{URL: "https://v.example/1.jpg"}, Width: 1280, Height: 720, Duration: 600},
+		AudioAttachment{Token: "aud", URL: "https://a.example/1", Transcription: "hello"},
+		FileAttachment{Token: "doc", URL: "https://f.example/1", FileName: "report.pdf", Size: 1024},
+		StickerAttachment{Code: "smile", URL: "https://s.example/1", Width: 128, Height: 64},
+		ContactAttachment{VCFInfo: "BEGIN:VCARD", MaxInfo: &User{UserID: 7, FirstName: "Ann"}},
+		ShareAttachment{URL: "https://max.ru", Title: "MAX", Description: "messenger", ImageURL: "https://max.ru/i.png"},
+		LocationAttachment{Latitude: 55.75, Longitude: 37.61},
+		ReplyKeyboardAttachment{Buttons: [][]*Button{{{Type: "message", Text: "Yes"}}}},
+		DataAttachment{Data: "payload"},
+		nil,
+	}
+
+	for i, attach := range attachments {
+		typed := attach.Typed()
+		if !reflect.DeepEqual(typed, expected[i]) {
+			t.Errorf("%s: expected %#v, got %#v", attach.Type, expected[i], typed)
+		}
+		if typed != nil && typed.AttachmentType() != attach.Type {
+			t.Errorf("%s: unexpected AttachmentType %s", attach.Type, typed.AttachmentType())
+		}
+	}
+}
+
+func TestAttachmentTyped_ContactWithoutMaxInfo(t *testing.T) {
+	attach := Attachment{Type: AttachContact, Payload: Payload{VCFInfo: "BEGIN:VCARD"}}
+
+	contact, ok := attach.Typed().(ContactAttachment)
+	if !ok {
+		t.Fatalf("Expected ContactAttachment, got %T", attach.Typed())
+	}
+	if contact.MaxInfo != nil {
+		t.Errorf("Expected nil MaxInfo, got %+v", contact.MaxInfo)
+	}
+}
+
+func TestAttachmentMarshal_OmitsEmptyFields(t *testing.T) {
+	data, err := json.Marshal(Attachment{Type: AttachImage, Payload: Payload{Token: "img"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, field := range []string{"vcf_info", "max_info", "hash", "filename", "size"} {
+		if strings.Contains(string(data), field) {
+			t.Errorf("Expected %s to be omitted: %s", field, data)
+		}
+	}
+}
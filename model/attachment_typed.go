@@ -0,0 +1,149 @@
+package model
+
+// TypedAttachment вложение конкретного типа. Возвращается Attachment.Typed:
+//
+//	switch a := attach.Typed().(type) {
+//	case PhotoAttachment:
+//	case FileAttachment:
+//	}
+type TypedAttachment interface {
+	AttachmentType() AttachmentType
+}
+
+type PhotoAttachment struct {
+	PhotoID int64
+	Token   string
+	URL     string
+}
+
+// VideoAttachment видео. Ссылки на файлы разного качества возвращает GetVideoAttachmentDetails.
+type VideoAttachment struct {
+	Token     string
+	URL       string
+	Thumbnail *VideoThumbnail
+	Width     int
+	Height    int
+	Duration  int
+}
+
+type AudioAttachment struct {
+	Token         string
+	URL           string
+	Transcription string
+}
+
+type FileAttachment struct {
+	Token    string
+	URL      string
+	FileName string
+	Size     int64
+}
+
+type StickerAttachment struct {
+	Code   string
+	URL    string
+	Width  int
+	Height int
+}
+
+type ContactAttachment struct {
+	// VCFInfo контакт в формате vCard.
+	VCFInfo string
+	// MaxInfo пользователь MAX, nil для контакта не из MAX.
+	MaxInfo *User
+}
+
+// VCard разбирает VCFInfo.
+func (c ContactAttachment) VCard() (VCard, error) {
+	return ParseVCard(c.VCFInfo)
+}
+
+type ShareAttachment struct {
+	Token       string
+	URL         string
+	Title       string
+	Description string
+	ImageURL    string
+}
+
+type LocationAttachment struct {
+	Latitude  float64
+	Longitude float64
+}
+
+type InlineKeyboardAttachment struct {
+	Buttons [][]*Button
+}
+
+type ReplyKeyboardAttachment struct {
+	Buttons [][]*Button
+}
+
+// DataAttachment содержит payload кнопки SendMessageButton.
+type DataAttachment struct {
+	Data string
+}
+
+func (PhotoAttachment) AttachmentType() AttachmentType          { return AttachImage }
+func (VideoAttachment) AttachmentType() AttachmentType          { return AttachVideo }
+func (AudioAttachment) AttachmentType() AttachmentType          { return AttachAudio }
+func (FileAttachment) AttachmentType() AttachmentType           { return AttachFile }
+func (StickerAttachment) AttachmentType() AttachmentType        { return AttachSticker }
+func (ContactAttachment) AttachmentType() AttachmentType        { return AttachContact }
+func (ShareAttachment) AttachmentType() AttachmentType          { return AttachShare }
+func (LocationAttachment) AttachmentType() AttachmentType       { return AttachLocation }
+func (InlineKeyboardAttachment) AttachmentType() AttachmentType { return AttachInlineKeyboard }
+func (ReplyKeyboardAttachment) AttachmentType() AttachmentType  { return AttachReplyKeyboard }
+func (DataAttachment) AttachmentType() AttachmentType           { return AttachData }
+
+// Typed возвращает вложение в виде структуры, соответствующей Type, с полями из схемы API.
+// Для неизвестного типа возвращает nil.
+func (a Attachment) Typed() TypedAttachment {
+	p := a.Payload
+
+	switch a.Type {
+	case AttachImage:
+		return PhotoAttachment{PhotoID: p.PhotoID, Token: p.Token, URL: p.URL}
+	case AttachVideo:
+		return VideoAttachment{
+			Token:     p.Token,
+			URL:       p.URL,
+			Thumbnail: a.Thumbnail,
+			Width:     a.Width,
+			Height:    a.Height,
+			Duration:  a.Duration,
+		}
+	case AttachAudio:
+		return AudioAttachment{Token: p.Token, URL: p.URL, Transcription: a.Transcription}
+	case AttachFile:
+		return FileAttachment{Token: p.Token, URL: p.URL, FileName: a.FileName, Size: int64(a.Size)}
+	case AttachSticker:
+		return StickerAttachment{Code: p.Code, URL: p.URL, Width: a.Width, Height: a.Height}
+	case AttachContact:
+		contact := ContactAttachment{VCFInfo: p.VCFInfo}
+		if p.MaxInfo != (User{}) {
+			user := p.MaxInfo
+			contact.MaxInfo = &user
+		}
+
+		return contact
+	case AttachShare:
+		return ShareAttachment{
+			Token:       p.Token,
+			URL:         p.URL,
+			Title:       a.Title,
+			Description: a.Description,
+			ImageURL:    a.ImageURL,
+		}
+	case AttachLocation:
+		return LocationAttachment{Latitude: a.Latitude, Longitude: a.Longitude}
+	case AttachInlineKeyboard:
+		return InlineKeyboardAttachment{Buttons: p.Buttons}
+	case AttachReplyKeyboard:
+		return ReplyKeyboardAttachment{Buttons: a.Buttons}
+	case AttachData:
+		return DataAttachment{Data: a.Data}
+	}
+
+	return nil
+}
@@ -24,6 +24,24 @@ type MessageUpdate struct {
 	Link      *LinkedMessage
 }
 
+// Forwarded возвращает исходное сообщение, если сообщение переслано.
+func (m MessageUpdate) Forwarded() (LinkedMessage, bool) {
+	if m.Link == nil || !m.Link.IsForward() {
+		return LinkedMessage{}, false
+	}
+
+	return *m.Link, true
+}
+
+// ReplyTo возвращает сообщение, на которое дан ответ.
+func (m MessageUpdate) ReplyTo() (LinkedMessage, bool) {
+	if m.Link == nil || !m.Link.IsReply() {
+		return LinkedMessage{}, false
+	}
+
+	return *m.Link, true
+}
+
 type MessageStat struct {
 	Views int `json:"views"`
 }
@@ -52,11 +70,31 @@ type NewMessageLink struct {
 	Mid  string          `json:"mid"`
 }
 
+// LinkedMessage пересланное сообщение или сообщение, на которое дан ответ.
 type LinkedMessage struct {
-	Type    MessageLinkType `json:"type"`
-	Sender  *User           `json:"sender,omitempty"`
-	ChatID  int64           `json:"chat_id,omitempty"`
-	Message MessageBody     `json:"message"`
+	Type MessageLinkType `json:"type"`
+	// Sender автор исходного сообщения, nil для сообщения от имени канала.
+	Sender *User `json:"sender,omitempty"`
+	// ChatID чат исходного сообщения, только для пересланных сообщений.
+	ChatID  int64       `json:"chat_id,omitempty"`
+	Message MessageBody `json:"message"`
+}
+
+func (l LinkedMessage) IsForward() bool {
+	return l.Type == LinkTypeForward
+}
+
+func (l LinkedMessage) IsReply() bool {
+	return l.Type == LinkTypeReply
+}
+
+// GetSender возвращает автора исходного сообщения или пустого пользователя для сообщения от имени канала.
+func (l LinkedMessage) GetSender() User {
+	if l.Sender != nil {
+		return *l.Sender
+	}
+
+	return User{}
 }
 
 type SendMessageResult struct {
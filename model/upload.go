@@ -1,5 +1,10 @@
 package model
 
+import (
+	"encoding/json"
+	"sort"
+)
+
 type UploadEndpoint struct {
 	Token string `json:"token,omitempty"`
 	Url   string `json:"url"`
@@ -12,3 +17,41 @@ type PhotoTokens struct {
 type PhotoToken struct {
 	Token string `json:"token"`
 }
+
+// UploadResult результат загрузки файла.
+type UploadResult struct {
+	Type UploadType `json:"type"`
+	// Token токен для отправки вложения. Для изображения - токен из Photos с наименьшим ключом.
+	Token string `json:"token"`
+	// Photos токены изображения с ключами, которые вернул сервер.
+	Photos map[string]PhotoToken `json:"photos,omitempty"`
+	// EndpointToken токен, выданный вместе со ссылкой для загрузки. Используется для аудио и видео.
+	EndpointToken string `json:"endpoint_token,omitempty"`
+	// Response исходный ответ сервера на загрузку файла.
+	Response json.RawMessage `json:"response,omitempty"`
+}
+
+// PhotoKeys возвращает отсортированные ключи Photos.
+func (r UploadResult) PhotoKeys() []string {
+	keys := make([]string, 0, len(r.Photos))
+	for k := range r.Photos {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// AttachmentType возвращает тип вложения, соответствующий типу загрузки.
+func (t UploadType) AttachmentType() AttachmentType {
+	switch t {
+	case UploadImage:
+		return AttachImage
+	case UploadVideo:
+		return AttachVideo
+	case UploadAudio:
+		return AttachAudio
+	default:
+		return AttachFile
+	}
+}
@@ -0,0 +1,202 @@
+package model
+
+import (
+	"errors"
+	"strings"
+)
+
+// VCard контакт в формате vCard (RFC 6350, также понимается версия 2.1 и 3.0).
+type VCard struct {
+	// Name отображаемое имя (FN). Если FN нет, собирается из N.
+	Name      string
+	FirstName string
+	LastName  string
+	Phones    []VCardPhone
+	Emails    []string
+}
+
+type VCardPhone struct {
+	Number string
+	// Types типы номера в нижнем регистре: cell, home, work и т.д.
+	Types []string
+}
+
+// Phone возвращает первый номер телефона.
+func (v VCard) Phone() string {
+	if len(v.Phones) == 0 {
+		return ""
+	}
+
+	return v.Phones[0].Number
+}
+
+// DisplayName возвращает Name или, если оно не задано, имя и фамилию.
+func (v VCard) DisplayName() string {
+	if v.Name != "" {
+		return v.Name
+	}
+
+	return strings.TrimSpace(v.FirstName + " " + v.LastName)
+}
+
+// ParseVCard разбирает контакт из VCFInfo вложения contact.
+func ParseVCard(data string) (card VCard, err error) {
+	lines := unfoldVCard(data)
+
+	var begin, end bool
+	for _, line := range lines {
+		name, params, value, ok := splitVCardLine(line)
+		if !ok {
+			continue
+		}
+
+		switch name {
+		case "BEGIN":
+			begin = strings.EqualFold(value, "VCARD")
+		case "END":
+			end = strings.EqualFold(value, "VCARD")
+		case "FN":
+			card.Name = unescapeVCard(value)
+		case "N":
+			parts := splitVCardValue(value)
+			if len(parts) > 0 {
+				card.LastName = parts[0]
+			}
+			if len(parts) > 1 {
+				card.FirstName = parts[1]
+			}
+		case "TEL":
+			number := strings.TrimPrefix(unescapeVCard(value), "tel:")
+			if number != "" {
+				card.Phones = append(card.Phones, VCardPhone{Number: number, Types: vCardTypes(params)})
+			}
+		case "EMAIL":
+			if email := unescapeVCard(value); email != "" {
+				card.Emails = append(card.Emails, email)
+			}
+		}
+	}
+
+	if !begin || !end {
+		err = errors.New("vcard: missing BEGIN:VCARD or END:VCARD")
+
+		return
+	}
+
+	card.Name = card.DisplayName()
+
+	return
+}
+
+// String возвращает контакт в формате vCard 3.0.
+func (v VCard) String() string {
+	var b strings.Builder
+	b.WriteString("BEGIN:VCARD\r\nVERSION:3.0\r\n")
+
+	b.WriteString("FN:" + escapeVCard(v.DisplayName()) + "\r\n")
+	b.WriteString("N:" + escapeVCard(v.LastName) + ";" + escapeVCard(v.FirstName) + ";;;\r\n")
+
+	for _, phone := range v.Phones {
+		b.WriteString("TEL")
+		if len(phone.Types) > 0 {
+			b.WriteString(";TYPE=" + strings.ToUpper(strings.Join(phone.Types, ",")))
+		}
+		b.WriteString(":" + escapeVCard(phone.Number) + "\r\n")
+	}
+	for _, email := range v.Emails {
+		b.WriteString("EMAIL:" + escapeVCard(email) + "\r\n")
+	}
+
+	b.WriteString("END:VCARD\r\n")
+
+	return b.String()
+}
+
+// unfoldVCard склеивает перенесённые строки: продолжение начинается с пробела или табуляции.
+func unfoldVCard(data string) []string {
+	data = strings.ReplaceAll(data, "\r\n", "\n")
+
+	var lines []string
+	for _, line := range strings.Split(data, "\n") {
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	return lines
+}
+
+// splitVCardLine разбирает строку вида "item1.TEL;TYPE=CELL:+79990000000".
+func splitVCardLine(line string) (name string, params []string, value string, ok bool) {
+	head, value, ok := strings.Cut(line, ":")
+	if !ok {
+		return
+	}
+
+	parts := strings.Split(head, ";")
+	name = strings.ToUpper(strings.TrimSpace(parts[0]))
+	// группа свойства, например item1.TEL
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		name = name[i+1:]
+	}
+
+	return name, parts[1:], strings.TrimSpace(value), true
+}
+
+// vCardTypes извлекает типы из параметров TYPE=CELL,VOICE и из параметров без имени (vCard 2.1: TEL;CELL).
+func vCardTypes(params []string) []string {
+	var types []string
+	for _, param := range params {
+		key, value, ok := strings.Cut(param, "=")
+		if !ok {
+			value = key
+		} else if !strings.EqualFold(key, "TYPE") {
+			continue
+		}
+
+		for _, t := range strings.Split(strings.Trim(value, `"`), ",") {
+			if t = strings.ToLower(strings.TrimSpace(t)); t != "" {
+				types = append(types, t)
+			}
+		}
+	}
+
+	return types
+}
+
+// splitVCardValue делит составное значение по неэкранированной ";".
+func splitVCardValue(value string) []string {
+	var parts []string
+	var cur strings.Builder
+	for i := 0; i < len(value); i++ {
+		switch {
+		case value[i] == '\\' && i+1 < len(value):
+			cur.WriteByte('\\')
+			cur.WriteByte(value[i+1])
+			i++
+		case value[i] == ';':
+			parts = append(parts, unescapeVCard(cur.String()))
+			cur.Reset()
+		default:
+			cur.WriteByte(value[i])
+		}
+	}
+
+	return append(parts, unescapeVCard(cur.String()))
+}
+
+var (
+	vCardEscaper   = strings.NewReplacer(`\`, `\\`, "\n", `\n`, ",", `\,`, ";", `\;`)
+	vCardUnescaper = strings.NewReplacer(`\\`, `\`, `\n`, "\n", `\N`, "\n", `\,`, ",", `\;`, ";")
+)
+
+func escapeVCard(s string) string {
+	return vCardEscaper.Replace(s)
+}
+
+func unescapeVCard(s string) string {
+	return vCardUnescaper.Replace(s)
+}
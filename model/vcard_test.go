@@ -0,0 +1,96 @@
+package model
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseVCard(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected VCard
+		wantErr  bool
+	}{
+		{
+			name: "vcard 3.0",
+			data: "BEGIN:VCARD\r\nVERSION:3.0\r\nFN:Ivan Petrov\r\nN:Petrov;Ivan;;;\r\n" +
+				"TEL;TYPE=CELL,VOICE:+79990000001\r\nitem1.TEL;type=WORK:+74950000002\r\n" +
+				"EMAIL;TYPE=INTERNET:ivan@example.com\r\nEND:VCARD\r\n",
+			expected: VCard{
+				Name:      "Ivan Petrov",
+				FirstName: "Ivan",
+				LastName:  "Petrov",
+				Phones: []VCardPhone{
+					{Number: "+79990000001", Types: []string{"cell", "voice"}},
+					{Number: "+74950000002", Types: []string{"work"}},
+				},
+				Emails: []string{"ivan@example.com"},
+			},
+		},
+		{
+			name: "vcard 2.1 without FN and with folded line",
+			data: "BEGIN:VCARD\nVERSION:2.1\nN:Smith;John\nTEL;CELL:+1555\n 0100\nEND:VCARD",
+			expected: VCard{
+				Name:      "John Smith",
+				FirstName: "John",
+				LastName:  "Smith",
+				Phones:    []VCardPhone{{Number: "+15550100", Types: []string{"cell"}}},
+			},
+		},
+		{
+			name: "vcard 4.0 tel uri and escaping",
+			data: "BEGIN:VCARD\nVERSION:4.0\nFN:Acme\\, Inc.\nTEL;VALUE=uri:tel:+79990000003\nEND:VCARD",
+			expected: VCard{
+				Name:   "Acme, Inc.",
+				Phones: []VCardPhone{{Number: "+79990000003"}},
+			},
+		},
+		{
+			name:    "not a vcard",
+			data:    "FN:Ivan",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			card, err := ParseVCard(tt.data)
+			if tt.wantErr {
+				if err == nil {
+					t.Error("Expected error, got nil")
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(card, tt.expected) {
+				t.Errorf("Expected %+v, got %+v", tt.expected, card)
+			}
+		})
+	}
+}
+
+func TestVCard_String(t *testing.T) {
+	card := VCard{
+		FirstName: "Ivan",
+		LastName:  "Petrov; Jr.",
+		Phones:    []VCardPhone{{Number: "+79990000001", Types: []string{"cell"}}},
+		Emails:    []string{"ivan@example.com"},
+	}
+
+	parsed, err := ParseVCard(card.String())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	card.Name = "Ivan Petrov; Jr."
+	if !reflect.DeepEqual(parsed, card) {
+		t.Errorf("Expected %+v, got %+v", card, parsed)
+	}
+	if parsed.Phone() != "+79990000001" {
+		t.Errorf("Unexpected phone %q", parsed.Phone())
+	}
+}
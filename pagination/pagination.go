@@ -0,0 +1,249 @@
+// Package pagination показывает длинный список кнопок постранично с кнопками «назад» и «вперёд».
+//
+// Номер страницы хранится в payload кнопок навигации: pg:<id>:<page>. Нажатия на них обрабатывает
+// Middleware - сообщение со списком заменяется следующей страницей через ответ на нажатие.
+// Нажатия на кнопки элементов передаются следующему обработчику. Если страницу загрузить не удалось,
+// пользователь видит уведомление, а ошибка передаётся в обработчик WithErrorHandler.
+//
+//	pager, err := pagination.New(api.ExtendedMessages(), "catalog", pagination.Slice(items), pagination.WithPageSize(8))
+//	msg, err := pager.Message(ctx, 0)
+//	_, err = api.Messages.Send(ctx, msg.SetChat(chatID))
+//	handler = pager.Middleware(handler)
+package pagination
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	maxbot "github.com/max-messenger/max-bot-api-client-go/v2"
+	"github.com/max-messenger/max-bot-api-client-go/v2/internal/callbacks"
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+const (
+	kind = "pg"
+	// currentPage payload кнопки с номером текущей страницы
+	currentPage = "-"
+
+	defaultPageSize = 10
+	defaultColumns  = 1
+)
+
+// Item кнопка элемента списка. Payload получает следующий обработчик при нажатии.
+type Item struct {
+	Text    string
+	Payload string
+}
+
+// Source возвращает limit элементов, начиная с offset, и общее число элементов.
+type Source func(ctx context.Context, offset, limit int) (items []Item, total int, err error)
+
+// Slice источник элементов из среза.
+func Slice(items []Item) Source {
+	return func(_ context.Context, offset, limit int) ([]Item, int, error) {
+		offset = min(offset, len(items))
+		end := min(offset+limit, len(items))
+
+		return items[offset:end], len(items), nil
+	}
+}
+
+// Page страница списка. Number начинается с 0.
+type Page struct {
+	Number int
+	Count  int
+	Total  int
+	Items  []Item
+}
+
+type Pager struct {
+	router    *callbacks.Router
+	source    Source
+	pageSize  int
+	columns   int
+	prev      string
+	next      string
+	text      func(Page) string
+	format    model.TextFormat
+	errorText string
+	onError   callbacks.ErrorHandler
+}
+
+type Option func(p *Pager)
+
+// WithPageSize задаёт число элементов на странице.
+func WithPageSize(size int) Option {
+	return func(p *Pager) {
+		p.pageSize = size
+	}
+}
+
+// WithColumns задаёт число кнопок элементов в ряду.
+func WithColumns(columns int) Option {
+	return func(p *Pager) {
+		p.columns = columns
+	}
+}
+
+// WithLabels задаёт надписи кнопок навигации.
+func WithLabels(prev, next string) Option {
+	return func(p *Pager) {
+		p.prev, p.next = prev, next
+	}
+}
+
+// WithText задаёт текст сообщения над клавиатурой.
+func WithText(text func(Page) string, format model.TextFormat) Option {
+	return func(p *Pager) {
+		p.text, p.format = text, format
+	}
+}
+
+// WithErrorText задаёт уведомление, которое пользователь видит, если страницу не удалось загрузить.
+func WithErrorText(text string) Option {
+	return func(p *Pager) {
+		p.errorText = text
+	}
+}
+
+// WithErrorHandler задаёт обработчик ошибок загрузки страницы и ответа на нажатие.
+// По умолчанию ошибки отбрасываются.
+func WithErrorHandler(onError func(ctx context.Context, update model.Update, err error)) Option {
+	return func(p *Pager) {
+		p.onError = onError
+	}
+}
+
+// New создаёт список id. id отличает нажатия на кнопки навигации этого списка от других списков.
+func New(messages maxbot.CallbackAnswerer, id string, source Source, opts ...Option) (*Pager, error) {
+	p := &Pager{
+		source:    source,
+		pageSize:  defaultPageSize,
+		columns:   defaultColumns,
+		prev:      "«",
+		next:      "»",
+		errorText: "Не удалось загрузить страницу",
+		text: func(page Page) string {
+			return fmt.Sprintf("Страница %d из %d", page.Number+1, page.Count)
+		},
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+
+	switch {
+	case source == nil:
+		return nil, fmt.Errorf("pagination: nil source")
+	case p.columns < 1 || p.columns > maxbot.MaxButtonsPerRow:
+		return nil, fmt.Errorf("pagination: columns must be between 1 and %d, got %d", maxbot.MaxButtonsPerRow, p.columns)
+	case p.pageSize < 1 || p.pageSize > (maxbot.MaxKeyboardRows-1)*p.columns:
+		return nil, fmt.Errorf("pagination: page size %d does not fit keyboard", p.pageSize)
+	}
+
+	router, err := callbacks.New(messages, kind, id, p.handle)
+	if err != nil {
+		return nil, fmt.Errorf("pagination: %w", err)
+	}
+	router.ErrorText, router.OnError = p.errorText, p.onError
+	p.router = router
+
+	return p, nil
+}
+
+// Page загружает страницу number. Номер за пределами списка заменяется ближайшей страницей.
+func (p *Pager) Page(ctx context.Context, number int) (page Page, err error) {
+	number = max(number, 0)
+
+	items, total, err := p.source(ctx, number*p.pageSize, p.pageSize)
+	if err != nil {
+		err = fmt.Errorf("pagination: load page %d: %w", number, err)
+
+		return
+	}
+
+	count := max((total+p.pageSize-1)/p.pageSize, 1)
+	if number >= count {
+		// список стал короче, показываем последнюю страницу
+		number = count - 1
+		if items, total, err = p.source(ctx, number*p.pageSize, p.pageSize); err != nil {
+			err = fmt.Errorf("pagination: load page %d: %w", number, err)
+
+			return
+		}
+	}
+
+	return Page{Number: number, Count: count, Total: total, Items: items}, nil
+}
+
+// Keyboard возвращает клавиатуру страницы: кнопки элементов и ряд навигации.
+func (p *Pager) Keyboard(page Page) *model.Keyboard {
+	kb := model.NewKeyboard()
+
+	var row *model.KeyboardRow
+	for i, item := range page.Items {
+		if i%p.columns == 0 {
+			row = kb.AddRow()
+		}
+		row.AddCallBack(item.Text, item.Payload)
+	}
+
+	if page.Count > 1 {
+		nav := kb.AddRow()
+		if page.Number > 0 {
+			nav.AddCallBack(p.prev, p.payload(strconv.Itoa(page.Number-1)))
+		}
+		nav.AddCallBack(fmt.Sprintf("%d/%d", page.Number+1, page.Count), p.payload(currentPage))
+		if page.Number < page.Count-1 {
+			nav.AddCallBack(p.next, p.payload(strconv.Itoa(page.Number+1)))
+		}
+	}
+
+	return kb
+}
+
+// Message возвращает сообщение со страницей number. Получателя нужно задать перед отправкой.
+func (p *Pager) Message(ctx context.Context, number int) (*maxbot.Message, error) {
+	page, err := p.Page(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+
+	return p.message(page), nil
+}
+
+// Middleware обрабатывает нажатия на кнопки навигации списка и передаёт next все остальные обновления,
+// в том числе нажатия на кнопки элементов.
+func (p *Pager) Middleware(next maxbot.UpdateHandler) maxbot.UpdateHandler {
+	return p.router.Middleware(next)
+}
+
+func (p *Pager) handle(ctx context.Context, _ model.Update, value string) (*maxbot.CallbackAnswer, error) {
+	// кнопка с номером страницы ничего не меняет, нажатие только подтверждается
+	if value == currentPage {
+		return nil, nil
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil {
+		return nil, fmt.Errorf("pagination: invalid page %q", value)
+	}
+
+	page, err := p.Page(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+
+	return maxbot.NewCallbackAnswer().SetMessage(p.message(page)), nil
+}
+
+func (p *Pager) message(page Page) *maxbot.Message {
+	return maxbot.NewMessage().
+		SetText(p.text(page)).
+		SetFormat(p.format).
+		AddKeyboard(p.Keyboard(page))
+}
+
+func (p *Pager) payload(value string) string {
+	return p.router.Payload(value)
+}
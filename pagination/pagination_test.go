@@ -0,0 +1,178 @@
+package pagination
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	maxbot "github.com/max-messenger/max-bot-api-client-go/v2"
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+func testItems(n int) []Item {
+	items := make([]Item, 0, n)
+	for i := 1; i <= n; i++ {
+		items = append(items, Item{Text: "Item " + strconv.Itoa(i), Payload: "item:" + strconv.Itoa(i)})
+	}
+
+	return items
+}
+
+func buttons(t *testing.T, msg *maxbot.Message) [][]*model.Button {
+	t.Helper()
+
+	body := msg.MessageBody()
+	require.Len(t, body.Attachments, 1)
+
+	return body.Attachments[0].Payload.Buttons
+}
+
+func TestPager_Message(t *testing.T) {
+	pager, err := New(nil, "catalog", Slice(testItems(300)), WithPageSize(8), WithColumns(2))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	msg, err := pager.Message(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "Страница 1 из 38", msg.MessageBody().Text)
+
+	rows := buttons(t, msg)
+	require.Len(t, rows, 5)
+	assert.Equal(t, "Item 1", rows[0][0].Text)
+	assert.Equal(t, "item:2", rows[0][1].Payload)
+	require.Len(t, rows[4], 2)
+	assert.Equal(t, "pg:catalog:-", rows[4][0].Payload)
+	assert.Equal(t, "pg:catalog:1", rows[4][1].Payload)
+
+	msg, err = pager.Message(ctx, 100)
+	require.NoError(t, err)
+	rows = buttons(t, msg)
+	require.Len(t, rows, 3)
+	assert.Equal(t, []*model.Button{
+		{Text: "Item 297", Type: model.ButtonCallback, Payload: "item:297"},
+		{Text: "Item 298", Type: model.ButtonCallback, Payload: "item:298"},
+	}, rows[0])
+	require.Len(t, rows[2], 2)
+	assert.Equal(t, "pg:catalog:36", rows[2][0].Payload)
+	assert.Equal(t, "38/38", rows[2][1].Text)
+}
+
+func TestPager_SinglePage(t *testing.T) {
+	pager, err := New(nil, "short", Slice(testItems(3)))
+	require.NoError(t, err)
+
+	msg, err := pager.Message(context.Background(), 0)
+	require.NoError(t, err)
+	assert.Len(t, buttons(t, msg), 3)
+
+	pager, err = New(nil, "empty", Slice(nil))
+	require.NoError(t, err)
+
+	page, err := pager.Page(context.Background(), 2)
+	require.NoError(t, err)
+	assert.Equal(t, 0, page.Number)
+	assert.Equal(t, 1, page.Count)
+	assert.Empty(t, page.Items)
+}
+
+func TestNew_Invalid(t *testing.T) {
+	src := Slice(nil)
+
+	for name, fn := range map[string]func() (*Pager, error){
+		"empty id":    func() (*Pager, error) { return New(nil, "", src) },
+		"colon in id": func() (*Pager, error) { return New(nil, "a:b", src) },
+		"nil source":  func() (*Pager, error) { return New(nil, "a", nil) },
+		"columns":     func() (*Pager, error) { return New(nil, "a", src, WithColumns(8)) },
+		"page size":   func() (*Pager, error) { return New(nil, "a", src, WithPageSize(30)) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := fn()
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestPager_Middleware(t *testing.T) {
+	var query, body string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		query, body = r.URL.RawQuery, string(data)
+		_, _ = w.Write([]byte(`{"success":true}`))
+	}))
+	defer srv.Close()
+
+	api, err := maxbot.NewApi("token", maxbot.WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	pager, err := New(api.ExtendedMessages(), "catalog", Slice(testItems(25)))
+	require.NoError(t, err)
+
+	var passed []string
+	handler := pager.Middleware(func(_ context.Context, update model.Update) {
+		passed = append(passed, update.GetCallback().Payload)
+	})
+	callback := func(payload string) model.Update {
+		return model.Update{Callback: &model.Callback{CallbackID: "cb.1", Payload: payload}}
+	}
+
+	handler(context.Background(), callback("pg:catalog:2"))
+	assert.Equal(t, "callback_id=cb.1", query)
+
+	var answer model.CallbackAnswer
+	require.NoError(t, json.Unmarshal([]byte(body), &answer))
+	require.NotNil(t, answer.Message)
+	assert.Equal(t, "Страница 3 из 3", answer.Message.Text)
+	rows := answer.Message.Attachments[0].Payload.Buttons
+	require.Len(t, rows, 6)
+	assert.Equal(t, "Item 21", rows[0][0].Text)
+
+	handler(context.Background(), callback("pg:catalog:-"))
+	assert.JSONEq(t, `{}`, body)
+
+	handler(context.Background(), callback("item:21"))
+	handler(context.Background(), callback("pg:other:1"))
+	handler(context.Background(), model.Update{})
+	assert.Equal(t, []string{"item:21", "pg:other:1", ""}, passed)
+}
+
+func TestPager_Middleware_SourceError(t *testing.T) {
+	var bodies []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		data, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(data))
+		_, _ = w.Write([]byte(`{"success":true}`))
+	}))
+	defer srv.Close()
+
+	api, err := maxbot.NewApi("token", maxbot.WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	var errs []error
+	source := func(context.Context, int, int) ([]Item, int, error) {
+		return nil, 0, errors.New("database is down")
+	}
+	pager, err := New(api.ExtendedMessages(), "catalog", source, WithErrorHandler(func(_ context.Context, _ model.Update, err error) {
+		errs = append(errs, err)
+	}))
+	require.NoError(t, err)
+
+	handler := pager.Middleware(func(context.Context, model.Update) {})
+	handler(context.Background(), model.Update{Callback: &model.Callback{CallbackID: "cb.1", Payload: "pg:catalog:1"}})
+	handler(context.Background(), model.Update{Callback: &model.Callback{CallbackID: "cb.2", Payload: "pg:catalog:x"}})
+
+	assert.Equal(t, []string{
+		`{"notification":"Не удалось загрузить страницу"}`,
+		`{"notification":"Не удалось загрузить страницу"}`,
+	}, bodies)
+	require.Len(t, errs, 2)
+	assert.ErrorContains(t, errs[0], "database is down")
+	assert.ErrorContains(t, errs[1], "invalid page")
+}
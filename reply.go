@@ -0,0 +1,54 @@
+package maxbot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+// NewReply создаёт сообщение, адресованное туда, откуда пришло update: в чат, канал или диалог,
+// для нажатия кнопки - в чат сообщения с кнопкой.
+func NewReply(update model.Update) *Message {
+	msg := NewMessage()
+	setReplyRecipient(msg, update)
+
+	return msg
+}
+
+// NewQuotedReply создаёт ответ как NewReply с цитатой сообщения из update.
+func NewQuotedReply(update model.Update) *Message {
+	msg := NewReply(update)
+	if mid := update.GetMessage().Body.Mid; mid != "" {
+		msg.message.Link = &model.NewMessageLink{Type: model.LinkTypeReply, Mid: mid}
+	}
+
+	return msg
+}
+
+// Reply отправляет msg туда, откуда пришло update. Получатель msg заменяется.
+func (a *Api) Reply(ctx context.Context, update model.Update, msg *Message) (model.SendMessageResult, error) {
+	if msg == nil {
+		return model.SendMessageResult{}, fmt.Errorf("nil message")
+	}
+
+	reply := *msg
+	setReplyRecipient(&reply, update)
+
+	return a.Messages.Send(ctx, &reply)
+}
+
+func setReplyRecipient(msg *Message, update model.Update) {
+	msg.chatID, msg.userID = 0, 0
+
+	switch {
+	case update.ChatID != 0:
+		msg.chatID = update.ChatID
+	case update.Message != nil && update.Message.Recipient.ChatID != 0:
+		msg.chatID = update.Message.Recipient.ChatID
+	case update.Callback != nil && update.Callback.User.UserID != 0:
+		msg.userID = update.Callback.User.UserID
+	default:
+		msg.userID = update.UserID
+	}
+}
@@ -0,0 +1,96 @@
+package maxbot
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+func TestNewReply(t *testing.T) {
+	tests := []struct {
+		name   string
+		file   string
+		update model.Update
+		chatID int64
+		userID int64
+	}{
+		{name: "dialog message", file: "stabs/update.message_created.json"},
+		{name: "callback", file: "stabs/update.message_callback.json"},
+		{name: "bot started", file: "stabs/update.bot_started.json"},
+		{
+			name:   "channel",
+			update: model.Update{ChatID: -300, IsChannel: true},
+			chatID: -300,
+		},
+		{
+			name:   "callback without chat",
+			update: model.Update{UserID: 1, Callback: &model.Callback{User: model.User{UserID: 2}}},
+			userID: 2,
+		},
+		{
+			name:   "user only",
+			update: model.Update{UserID: 3},
+			userID: 3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			update := tt.update
+			if tt.file != "" {
+				data, err := stabs.ReadFile(tt.file)
+				require.NoError(t, err)
+
+				var list updateList
+				require.NoError(t, json.Unmarshal(data, &list))
+				update = list.Updates[0].FromRaw()
+				require.NotZero(t, update.ChatID)
+				tt.chatID = update.ChatID
+			}
+
+			msg := NewReply(update)
+			assert.Equal(t, tt.chatID, msg.chatID)
+			assert.Equal(t, tt.userID, msg.userID)
+		})
+	}
+}
+
+func TestNewQuotedReply(t *testing.T) {
+	update := model.Update{
+		ChatID:  100,
+		Message: &model.MessageUpdate{Body: model.MessageBody{Mid: "mid.1"}},
+	}
+
+	msg := NewQuotedReply(update)
+	assert.Equal(t, int64(100), msg.chatID)
+	assert.Equal(t, &model.NewMessageLink{Type: model.LinkTypeReply, Mid: "mid.1"}, msg.message.Link)
+
+	assert.Nil(t, NewQuotedReply(model.Update{UserID: 1}).message.Link)
+}
+
+func TestApi_Reply(t *testing.T) {
+	var query string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.ReadAll(r.Body)
+		query = r.URL.RawQuery
+		_, _ = w.Write([]byte(`{"message":{}}`))
+	}))
+	defer srv.Close()
+
+	api, err := NewApi(testToken, WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	msg := NewMessage().SetUser(999).SetText("hi")
+	_, err = api.Reply(context.Background(), model.Update{ChatID: 100, UserID: 5}, msg)
+	require.NoError(t, err)
+	assert.Equal(t, "chat_id=100", query)
+	assert.Equal(t, int64(999), msg.userID, "original message must not change")
+}
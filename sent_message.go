@@ -0,0 +1,161 @@
+package maxbot
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+// SentMessage отправленное сообщение, которое можно изменить, удалить, закрепить или на которое можно ответить.
+type SentMessage struct {
+	messages *Messages
+	chats    *Chats
+	format   model.TextFormat
+
+	Message model.Message
+}
+
+// SendMessage отправляет сообщение и возвращает SentMessage для дальнейшей работы с ним.
+func (m *Messages) SendMessage(ctx context.Context, msg *Message) (*SentMessage, error) {
+	res, err := m.Send(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	sent := m.SentMessage(res.Message)
+	sent.format = msg.message.Format
+
+	return sent, nil
+}
+
+// SentMessage возвращает SentMessage для уже существующего сообщения, например из Update.
+func (m *Messages) SentMessage(msg model.Message) *SentMessage {
+	return &SentMessage{
+		messages: m,
+		chats:    newChats(m.client),
+		Message:  msg,
+	}
+}
+
+// ID возвращает идентификатор сообщения.
+func (s *SentMessage) ID() string {
+	return s.Message.Body.Mid
+}
+
+// Edit заменяет текст сообщения. Вложения и клавиатура сохраняются.
+func (s *SentMessage) Edit(ctx context.Context, text string) error {
+	// attachments: null оставляет вложения без изменений
+	res, err := s.messages.EditMessage(ctx, s.ID(), model.NewMessageBody{
+		Text:   text,
+		Format: s.format,
+	})
+	if err = simpleResultError("edit message", res, err); err != nil {
+		return err
+	}
+
+	s.Message.Body.Text = text
+
+	return nil
+}
+
+// EditKeyboard заменяет клавиатуру сообщения. nil удаляет клавиатуру. Текст и остальные вложения сохраняются.
+func (s *SentMessage) EditKeyboard(ctx context.Context, keyboard *model.Keyboard) error {
+	attachments := make([]model.Attachment, 0, len(s.Message.Body.Attachments)+1)
+	for _, attach := range s.Message.Body.Attachments {
+		if attach.Type == model.AttachInlineKeyboard {
+			continue
+		}
+		if req, ok := attachmentRequest(attach); ok {
+			attachments = append(attachments, req)
+		}
+	}
+	if keyboard != nil {
+		attachments = append(attachments, keyboard.Build())
+	}
+
+	res, err := s.messages.EditMessage(ctx, s.ID(), model.NewMessageBody{
+		Attachments: attachments,
+	})
+	if err = simpleResultError("edit keyboard", res, err); err != nil {
+		return err
+	}
+
+	s.Message.Body.Attachments = attachments
+
+	return nil
+}
+
+// Delete удаляет сообщение.
+func (s *SentMessage) Delete(ctx context.Context) error {
+	res, err := s.messages.DeleteMessage(ctx, s.ID())
+
+	return simpleResultError("delete message", res, err)
+}
+
+// Reply отправляет msg в тот же чат ответом на сообщение.
+func (s *SentMessage) Reply(ctx context.Context, msg *Message) (*SentMessage, error) {
+	reply := *msg
+	if s.Message.Recipient.ChatID != 0 {
+		reply.chatID, reply.userID = s.Message.Recipient.ChatID, 0
+	} else {
+		reply.chatID, reply.userID = 0, s.Message.Recipient.UserID
+	}
+	reply.message.Link = &model.NewMessageLink{Type: model.LinkTypeReply, Mid: s.ID()}
+
+	return s.messages.SendMessage(ctx, &reply)
+}
+
+// Pin закрепляет сообщение в чате. notify - уведомить участников чата.
+func (s *SentMessage) Pin(ctx context.Context, notify bool) error {
+	res, err := s.chats.PinMessage(ctx, s.Message.Recipient.ChatID, s.ID(), notify)
+
+	return simpleResultError("pin message", res, err)
+}
+
+// attachmentRequest преобразует полученное вложение в вложение для отправки.
+func attachmentRequest(attach model.Attachment) (model.Attachment, bool) {
+	req := model.Attachment{Type: attach.Type}
+
+	switch attach.Type {
+	case model.AttachImage, model.AttachVideo, model.AttachAudio, model.AttachFile:
+		if attach.Payload.Token == "" {
+			return req, false
+		}
+		req.Payload.Token = attach.Payload.Token
+	case model.AttachSticker:
+		req.Payload.Code = attach.Payload.Code
+	case model.AttachShare:
+		req.Payload.Token = attach.Payload.Token
+		if req.Payload.Token == "" {
+			req.Payload.URL = attach.Payload.URL
+		}
+	case model.AttachLocation:
+		req.Latitude, req.Longitude = attach.Latitude, attach.Longitude
+	case model.AttachContact:
+		if attach.Payload.MaxInfo.UserID != 0 {
+			req.Payload.ContactID = attach.Payload.MaxInfo.UserID
+			req.Payload.Name = attach.Payload.MaxInfo.Name
+		} else {
+			req.Payload.VCFInfo = attach.Payload.VCFInfo
+			if card, err := model.ParseVCard(attach.Payload.VCFInfo); err == nil {
+				req.Payload.Name = card.Name
+			}
+		}
+	default:
+		return req, false
+	}
+
+	return req, true
+}
+
+func simpleResultError(op string, res model.SimpleQueryResult, err error) error {
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	if !res.Success {
+		return fmt.Errorf("%s: %s", op, res.Message)
+	}
+
+	return nil
+}
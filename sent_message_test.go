@@ -0,0 +1,101 @@
+package maxbot
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+type recordedRequest struct {
+	Method string
+	Path   string
+	Query  string
+	Body   string
+}
+
+func newSentMessageServer(t *testing.T, requests *[]recordedRequest) *httptest.Server {
+	t.Helper()
+
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		*requests = append(*requests, recordedRequest{r.Method, r.URL.Path, r.URL.RawQuery, string(body)})
+
+		if r.Method == http.MethodPost && r.URL.Path == pathMessages {
+			_, _ = w.Write([]byte(`{"message":{
+				"recipient":{"chat_id":100,"chat_type":"chat"},
+				"body":{"mid":"mid.` + strconv.Itoa(len(*requests)) + `","text":"progress 0%","attachments":[
+					{"type":"image","payload":{"photo_id":1,"token":"img_token","url":"https://i.example/1.jpg"}},
+					{"type":"inline_keyboard","payload":{"buttons":[[{"type":"callback","text":"Cancel","payload":"cancel"}]]}}
+				]}}}`))
+
+			return
+		}
+		_, _ = w.Write([]byte(`{"success":true}`))
+	}))
+}
+
+func TestSentMessage(t *testing.T) {
+	var requests []recordedRequest
+	srv := newSentMessageServer(t, &requests)
+	defer srv.Close()
+
+	api, err := NewApi(testToken, WithBaseURL(srv.URL))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	sent, err := api.ExtendedMessages().SendMessage(ctx, NewMessage().SetChat(100).SetText("progress 0%").SetFormat(model.FormatHTML))
+	require.NoError(t, err)
+	assert.Equal(t, "mid.1", sent.ID())
+
+	require.NoError(t, sent.Edit(ctx, "<b>progress 50%</b>"))
+	assert.Equal(t, "message_id=mid.1", requests[1].Query)
+	assert.JSONEq(t, `{"text":"<b>progress 50%</b>","attachments":null,"format":"html"}`, requests[1].Body)
+
+	keyboard := model.NewKeyboard()
+	keyboard.AddRow().AddCallBack("Done", "done")
+	require.NoError(t, sent.EditKeyboard(ctx, keyboard))
+
+	var body model.NewMessageBody
+	require.NoError(t, json.Unmarshal([]byte(requests[2].Body), &body))
+	assert.Empty(t, body.Text)
+	require.Len(t, body.Attachments, 2)
+	assert.Equal(t, model.AttachImage, body.Attachments[0].Type)
+	assert.Equal(t, model.Payload{Token: "img_token"}, body.Attachments[0].Payload)
+	assert.Equal(t, "done", body.Attachments[1].Payload.Buttons[0][0].Payload)
+
+	require.NoError(t, sent.Pin(ctx, false))
+	assert.Equal(t, http.MethodPut, requests[3].Method)
+	assert.Equal(t, "/chats/100/pin", requests[3].Path)
+
+	reply, err := sent.Reply(ctx, NewMessage().SetUser(5).SetText("done"))
+	require.NoError(t, err)
+	assert.Equal(t, "chat_id=100", requests[4].Query)
+	assert.Contains(t, requests[4].Body, `"link":{"type":"reply","mid":"mid.1"}`)
+	assert.Equal(t, "mid.5", reply.ID())
+
+	require.NoError(t, sent.Delete(ctx))
+	assert.Equal(t, http.MethodDelete, requests[5].Method)
+	assert.Equal(t, "message_id=mid.1", requests[5].Query)
+}
+
+func TestSentMessage_Failure(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"success":false,"message":"message not found"}`))
+	}))
+	defer srv.Close()
+
+	api, err := NewApi(testToken, WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	sent := api.messages.SentMessage(model.Message{Body: model.MessageBody{Mid: "mid.1"}})
+	assert.EqualError(t, sent.Delete(context.Background()), "delete message: message not found")
+}
@@ -0,0 +1,309 @@
+package maxbot
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+// MaxTextLength максимальная длина текста сообщения в символах.
+const MaxTextLength = 4000
+
+// SendLong отправляет сообщение, разбивая слишком длинный текст на несколько сообщений по SplitText.
+// Вложения и клавиатура прикрепляются к последнему сообщению, ответ на сообщение - к первому.
+// При ошибке возвращаются результаты уже отправленных сообщений.
+func (m *Messages) SendLong(ctx context.Context, msg *Message) (res []model.SendMessageResult, err error) {
+	if msg == nil {
+		err = fmt.Errorf("nil message")
+
+		return
+	}
+
+	parts := SplitText(msg.message.Text, msg.message.Format, MaxTextLength)
+	for i, part := range parts {
+		chunk := *msg
+		chunk.message.Text = part
+		if i > 0 {
+			chunk.message.Link = nil
+		}
+		if i < len(parts)-1 {
+			chunk.message.Attachments = nil
+		}
+
+		var sent model.SendMessageResult
+		sent, err = m.Send(ctx, &chunk)
+		if err != nil {
+			err = fmt.Errorf("send part %d of %d: %w", i+1, len(parts), err)
+
+			return
+		}
+		res = append(res, sent)
+	}
+
+	return
+}
+
+// SplitText делит текст на части не длиннее limit символов. Текст делится по абзацам, затем
+// по строкам, затем по словам. Незакрытые HTML-теги, блоки кода и выделение Markdown закрываются
+// в конце части и открываются заново в начале следующей. limit <= 0 означает MaxTextLength.
+func SplitText(text string, format model.TextFormat, limit int) []string {
+	if limit <= 0 {
+		limit = MaxTextLength
+	}
+
+	var parts []string
+	for utf8.RuneCountInString(text) > limit {
+		budget := limit
+		var chunk, tail, closing, reopen string
+		for {
+			chunk, tail = cutText(text, budget, format)
+			closing, reopen = unclosedMarkup(chunk, format)
+
+			overflow := utf8.RuneCountInString(chunk) + utf8.RuneCountInString(closing) - limit
+			if overflow <= 0 || budget <= limit/2 {
+				break
+			}
+			budget -= overflow
+		}
+
+		parts = append(parts, chunk+closing)
+		text = reopen + tail
+	}
+
+	return append(parts, text)
+}
+
+// cutText отрезает начало text длиной не больше budget символов.
+func cutText(text string, budget int, format model.TextFormat) (chunk, tail string) {
+	window := text
+	if i := runeOffset(text, budget); i < len(text) {
+		window = text[:i]
+	}
+
+	for _, sep := range []string{"\n\n", "\n", " "} {
+		// разделитель сразу за окном позволяет взять окно целиком
+		if len(window) < len(text) && strings.HasPrefix(text[len(window):], sep) && markupStart(window, format) < 0 {
+			return window, text[len(window)+len(sep):]
+		}
+
+		i := lastSeparator(window, sep, format)
+		// абзац или строка, которые оставляют часть почти пустой, хуже деления по словам
+		if i > 0 && (sep == " " || i >= len(window)/2) {
+			return window[:i], text[i+len(sep):]
+		}
+	}
+
+	cut := len(window)
+	if i := markupStart(window, format); i > 0 {
+		cut = i
+	}
+
+	return window[:cut], text[cut:]
+}
+
+// lastSeparator ищет последний sep вне HTML-тега или сущности.
+func lastSeparator(s, sep string, format model.TextFormat) int {
+	for {
+		i := strings.LastIndex(s, sep)
+		if i <= 0 {
+			return -1
+		}
+		if markupStart(s[:i], format) < 0 {
+			return i
+		}
+		s = s[:i]
+	}
+}
+
+// markupStart возвращает начало незавершённого тега, сущности или экранирования в конце s, иначе -1.
+func markupStart(s string, format model.TextFormat) int {
+	if format == model.FormatHTML {
+		if i := strings.LastIndexByte(s, '<'); i > strings.LastIndexByte(s, '>') {
+			return i
+		}
+		if i := strings.LastIndexByte(s, '&'); i > strings.LastIndexByte(s, ';') && len(s)-i <= 10 {
+			return i
+		}
+
+		return -1
+	}
+
+	escaped := len(s) - len(strings.TrimRight(s, `\`))
+	if escaped%2 == 1 {
+		return len(s) - 1
+	}
+
+	return -1
+}
+
+var htmlTag = regexp.MustCompile(`<(/?)([a-zA-Z][a-zA-Z0-9]*)[^>]*>`)
+
+// unclosedMarkup возвращает теги, закрывающие разметку в конце chunk, и теги, открывающие её заново.
+func unclosedMarkup(chunk string, format model.TextFormat) (closing, reopen string) {
+	if format == model.FormatHTML {
+		type tag struct{ name, raw string }
+		var open []tag
+		for _, m := range htmlTag.FindAllStringSubmatch(chunk, -1) {
+			name := strings.ToLower(m[2])
+			if m[1] == "" {
+				if !strings.HasSuffix(m[0], "/>") && name != "br" {
+					open = append(open, tag{name: name, raw: m[0]})
+				}
+
+				continue
+			}
+			for i := len(open) - 1; i >= 0; i-- {
+				if open[i].name == name {
+					open = open[:i]
+
+					break
+				}
+			}
+		}
+
+		for i := len(open) - 1; i >= 0; i-- {
+			closing += "</" + open[i].name + ">"
+		}
+		for _, t := range open {
+			reopen += t.raw
+		}
+
+		return
+	}
+
+	var fence string
+	var inline []string
+	for _, line := range strings.Split(chunk, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") {
+			if fence == "" {
+				fence = trimmed
+			} else {
+				fence = ""
+			}
+
+			continue
+		}
+		if fence == "" {
+			inline = markdownSpans(line, inline)
+		}
+	}
+
+	for i := len(inline) - 1; i >= 0; i-- {
+		closing += inline[i]
+	}
+	reopen = strings.Join(inline, "")
+
+	if fence != "" {
+		marker := fence[:len(fence)-len(strings.TrimLeft(fence, "`"))]
+		closing = "\n" + marker + closing
+		reopen += fence + "\n"
+	}
+
+	return
+}
+
+// markdownMarkers маркеры выделения Markdown, которые могут переходить из одной части в другую.
+var markdownMarkers = []string{"**", "~~", "++", "^^", "_"}
+
+// markdownSpans добавляет к open маркеры, открытые в line, и убирает закрытые. Экранированные символы,
+// содержимое встроенного кода и адреса ссылок пропускаются, '_' внутри слова (my_var) маркером не считается.
+func markdownSpans(line string, open []string) []string {
+	i := 0
+	// встроенный код, начатый в предыдущей строке
+	if n := len(open); n > 0 && strings.HasPrefix(open[n-1], "`") {
+		end := strings.Index(line, open[n-1])
+		if end < 0 {
+			return open
+		}
+		i = end + len(open[n-1])
+		open = open[:n-1]
+	}
+
+	for i < len(line) {
+		if line[i] == '\\' {
+			i += 2
+
+			continue
+		}
+
+		if line[i] == '`' {
+			ticks := len(line[i:]) - len(strings.TrimLeft(line[i:], "`"))
+			marker := line[i : i+ticks]
+			if end := strings.Index(line[i+ticks:], marker); end >= 0 {
+				i += ticks + end + ticks
+
+				continue
+			}
+			// код не закрыт в этой строке и продолжается в следующей части
+			return toggleMarker(open, marker)
+		}
+
+		if strings.HasPrefix(line[i:], "](") {
+			if end := strings.IndexByte(line[i:], ')'); end >= 0 {
+				i += end + 1
+
+				continue
+			}
+		}
+		if line[i] == '_' && inWord(line, i) {
+			i++
+
+			continue
+		}
+
+		matched := false
+		for _, marker := range markdownMarkers {
+			if strings.HasPrefix(line[i:], marker) {
+				open = toggleMarker(open, marker)
+				i += len(marker)
+				matched = true
+
+				break
+			}
+		}
+		if !matched {
+			i++
+		}
+	}
+
+	return open
+}
+
+// inWord сообщает, что символ line[i] стоит между двумя буквами или цифрами.
+func inWord(line string, i int) bool {
+	prev, _ := utf8.DecodeLastRuneInString(line[:i])
+	next, _ := utf8.DecodeRuneInString(line[i+1:])
+
+	return isWordRune(prev) && isWordRune(next)
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+func toggleMarker(open []string, marker string) []string {
+	for i := len(open) - 1; i >= 0; i-- {
+		if open[i] == marker {
+			return append(open[:i:i], open[i+1:]...)
+		}
+	}
+
+	return append(open, marker)
+}
+
+func runeOffset(s string, n int) int {
+	for i := range s {
+		if n == 0 {
+			return i
+		}
+		n--
+	}
+
+	return len(s)
+}
@@ -0,0 +1,181 @@
+package maxbot
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+func TestSplitText(t *testing.T) {
+	tests := []struct {
+		name     string
+		text     string
+		format   model.TextFormat
+		limit    int
+		expected []string
+	}{
+		{
+			name:     "short text",
+			text:     "hello",
+			limit:    10,
+			expected: []string{"hello"},
+		},
+		{
+			name:     "paragraphs",
+			text:     "first paragraph\n\nsecond one",
+			limit:    20,
+			expected: []string{"first paragraph", "second one"},
+		},
+		{
+			name:     "lines",
+			text:     "line one\nline two\nline three",
+			limit:    20,
+			expected: []string{"line one\nline two", "line three"},
+		},
+		{
+			name:     "words",
+			text:     "один два три четыре",
+			limit:    10,
+			expected: []string{"один два", "три четыре"},
+		},
+		{
+			name:     "long word",
+			text:     "abcdefghij",
+			limit:    4,
+			expected: []string{"abcd", "efgh", "ij"},
+		},
+		{
+			name:     "html tags are balanced",
+			text:     `<b>bold <a href="https://max.ru">link text</a> tail</b>`,
+			format:   model.FormatHTML,
+			limit:    45,
+			expected: []string{`<b>bold <a href="https://max.ru">link</a></b>`, `<b><a href="https://max.ru">text</a> tail</b>`},
+		},
+		{
+			name:     "html entity is not cut",
+			text:     "aaaa&amp;b",
+			format:   model.FormatHTML,
+			limit:    7,
+			expected: []string{"aaaa", "&amp;b"},
+		},
+		{
+			name:   "markdown bold across parts",
+			text:   "**" + strings.TrimSpace(strings.Repeat("word ", 30)) + "**",
+			format: model.FormatMarkdown,
+			limit:  50,
+			expected: []string{
+				"**word word word word word word word word word**",
+				"**word word word word word word word word word**",
+				"**word word word word word word word word word**",
+				"**word word word**",
+			},
+		},
+		{
+			name:     "markdown nested and escaped markers",
+			text:     `~~a \_b _c d_ e~~`,
+			format:   model.FormatMarkdown,
+			limit:    12,
+			expected: []string{`~~a \_b~~`, `~~_c d_ e~~`},
+		},
+		{
+			name:     "markdown nested markers across parts",
+			text:     "**a _bb cc dd_ e**",
+			format:   model.FormatMarkdown,
+			limit:    12,
+			expected: []string{"**a _bb_**", "**_cc dd_**", "**e**"},
+		},
+		{
+			name:     "markdown inline code",
+			text:     "`x_1 y_2` _z_",
+			format:   model.FormatMarkdown,
+			limit:    6,
+			expected: []string{"`x_1`", "`y_2`", "_z_"},
+		},
+		{
+			name:   "markdown snake case words",
+			text:   strings.TrimSpace(strings.Repeat("see my_var and other_var here. ", 3)),
+			format: model.FormatMarkdown,
+			limit:  50,
+			expected: []string{
+				"see my_var and other_var here. see my_var and",
+				"other_var here. see my_var and other_var here.",
+			},
+		},
+		{
+			name:     "markdown link with underscore in url",
+			text:     "[link](https://ex.com/_a_b) _text_ tail words",
+			format:   model.FormatMarkdown,
+			limit:    40,
+			expected: []string{"[link](https://ex.com/_a_b) _text_ tail", "words"},
+		},
+		{
+			name:     "markdown code fence",
+			text:     "```go\nx := 1\ny := 2\n```",
+			format:   model.FormatMarkdown,
+			limit:    18,
+			expected: []string{"```go\nx := 1\n```", "```go\ny := 2\n```"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parts := SplitText(tt.text, tt.format, tt.limit)
+			assert.Equal(t, tt.expected, parts)
+			for _, part := range parts {
+				assert.LessOrEqual(t, utf8.RuneCountInString(part), max(tt.limit, 50))
+			}
+		})
+	}
+}
+
+func TestSplitText_Limit(t *testing.T) {
+	text := strings.Repeat("<i>слово</i> ", 2000)
+
+	parts := SplitText(text, model.FormatHTML, MaxTextLength)
+	require.Greater(t, len(parts), 1)
+	for _, part := range parts {
+		assert.LessOrEqual(t, utf8.RuneCountInString(part), MaxTextLength)
+		assert.Equal(t, strings.Count(part, "<i>"), strings.Count(part, "</i>"))
+	}
+}
+
+func TestMessages_SendLong(t *testing.T) {
+	var bodies []model.NewMessageBody
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body model.NewMessageBody
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		bodies = append(bodies, body)
+		_, _ = w.Write([]byte(`{"message":{"body":{"mid":"mid"}}}`))
+	}))
+	defer srv.Close()
+
+	api, err := NewApi(testToken, WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	keyboard := model.NewKeyboard()
+	keyboard.AddRow().AddCallBack("OK", "ok")
+
+	long := strings.Repeat("a", MaxTextLength) + "\n\nsummary"
+	msg := NewMessage().SetChat(1).SetReply(long, "mid.reply").AddKeyboard(keyboard)
+
+	res, err := api.ExtendedMessages().SendLong(context.Background(), msg)
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+	require.Len(t, bodies, 2)
+
+	assert.Empty(t, bodies[0].Attachments)
+	require.NotNil(t, bodies[0].Link)
+	assert.Nil(t, bodies[1].Link)
+	assert.Equal(t, "summary", bodies[1].Text)
+	require.Len(t, bodies[1].Attachments, 1)
+	assert.Equal(t, model.AttachInlineKeyboard, bodies[1].Attachments[0].Type)
+}
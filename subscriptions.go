@@ -68,7 +68,9 @@ func (s *Subscriptions) GetUpdates(ctx context.Context, marker int64) ([]model.U
 	}
 
 	for _, rawUpdate := range updateList.Updates {
-		res = append(res, rawUpdate.FromRaw())
+		update := rawUpdate.FromRaw()
+		s.client.onUpdate(ctx, update)
+		res = append(res, update)
 	}
 
 	return res, updateList.Marker, nil
@@ -85,8 +87,13 @@ func (s *Subscriptions) getUpdatesWithRetry(ctx context.Context, limit, timeout
 		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
 			return
 		}
+		// открытый circuit breaker не пропустит запрос до истечения OpenTimeout
+		if errors.Is(err, ErrCircuitOpen) {
+			return
+		}
 
 		if attempt < maxRetries-1 {
+			s.client.onRetry(ctx, newRequestInfo(http.MethodGet, pathUpdates, nil), attempt+1, err)
 			retryWait := time.Duration(1<<uint(attempt)) * time.Second
 			select {
 			case <-ctx.Done():
@@ -0,0 +1,96 @@
+package telemetry
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	MetricRequests        = "maxbot_api_requests_total"
+	MetricRequestDuration = "maxbot_api_request_duration_seconds"
+	MetricRetries         = "maxbot_api_retries_total"
+	MetricHandlerDuration = "maxbot_handler_duration_seconds"
+	MetricUploads         = "maxbot_uploads_total"
+	MetricUploadBytes     = "maxbot_upload_bytes_total"
+)
+
+// MetricExporter получает значения счётчиков и гистограмм.
+type MetricExporter interface {
+	AddCounter(name string, value float64, labels map[string]string)
+	RecordHistogram(name string, value float64, labels map[string]string)
+}
+
+// InMemoryMetrics хранит метрики в памяти. Подходит для тестов.
+type InMemoryMetrics struct {
+	mu         sync.Mutex
+	counters   map[string]float64
+	histograms map[string][]float64
+}
+
+func NewInMemoryMetrics() *InMemoryMetrics {
+	return &InMemoryMetrics{
+		counters:   make(map[string]float64),
+		histograms: make(map[string][]float64),
+	}
+}
+
+func (m *InMemoryMetrics) AddCounter(name string, value float64, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.counters[metricKey(name, labels)] += value
+}
+
+func (m *InMemoryMetrics) RecordHistogram(name string, value float64, labels map[string]string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	key := metricKey(name, labels)
+	m.histograms[key] = append(m.histograms[key], value)
+}
+
+// Counter возвращает значение счётчика с указанными метками.
+func (m *InMemoryMetrics) Counter(name string, labels map[string]string) float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.counters[metricKey(name, labels)]
+}
+
+// Histogram возвращает все записанные значения гистограммы с указанными метками.
+func (m *InMemoryMetrics) Histogram(name string, labels map[string]string) []float64 {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	values := m.histograms[metricKey(name, labels)]
+	res := make([]float64, len(values))
+	copy(res, values)
+
+	return res
+}
+
+type noopMetricExporter struct{}
+
+func (noopMetricExporter) AddCounter(string, float64, map[string]string) {}
+
+func (noopMetricExporter) RecordHistogram(string, float64, map[string]string) {}
+
+func metricKey(name string, labels map[string]string) string {
+	keys := make([]string, 0, len(labels))
+	for k := range labels {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	b := strings.Builder{}
+	b.WriteString(name)
+	for _, k := range keys {
+		b.WriteString("|")
+		b.WriteString(k)
+		b.WriteString("=")
+		b.WriteString(labels[k])
+	}
+
+	return b.String()
+}
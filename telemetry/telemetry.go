@@ -0,0 +1,155 @@
+// Package telemetry добавляет трассировку и метрики запросов к API и обработки обновлений.
+//
+// Загрузка файла получает span только для запроса адреса загрузки (POST /uploads): сам файл
+// отправляется на сервер загрузки мимо хуков запросов. Результат загрузки учитывается в метриках
+// MetricUploads и MetricUploadBytes.
+//
+//	tel := telemetry.New(telemetry.WithSpanExporter(exporter), telemetry.WithMetricExporter(metrics))
+//	api, err := maxbot.NewApi(token, maxbot.WithHook(tel.Hook()))
+//	handler := tel.Handler(myHandler)
+package telemetry
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	maxbot "github.com/max-messenger/max-bot-api-client-go/v2"
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+const (
+	AttrMethod     = "http.method"
+	AttrStatusCode = "http.status_code"
+	AttrEndpoint   = "maxbot.endpoint"
+	AttrChatID     = "maxbot.chat_id"
+	AttrUserID     = "maxbot.user_id"
+	AttrUpdateType = "maxbot.update_type"
+)
+
+type Telemetry struct {
+	spans   SpanExporter
+	metrics MetricExporter
+	now     func() time.Time
+}
+
+type Option func(t *Telemetry)
+
+func WithSpanExporter(e SpanExporter) Option {
+	return func(t *Telemetry) {
+		t.spans = e
+	}
+}
+
+func WithMetricExporter(e MetricExporter) Option {
+	return func(t *Telemetry) {
+		t.metrics = e
+	}
+}
+
+func New(opts ...Option) *Telemetry {
+	t := &Telemetry{
+		spans:   noopSpanExporter{},
+		metrics: noopMetricExporter{},
+		now:     time.Now,
+	}
+	for _, o := range opts {
+		o(t)
+	}
+
+	return t
+}
+
+// Hook возвращает обработчики для maxbot.WithHook.
+func (t *Telemetry) Hook() maxbot.Hook {
+	return maxbot.Hook{
+		OnRequest:  t.onRequest,
+		OnResponse: t.onResponse,
+		OnRetry:    t.onRetry,
+		OnUpload:   t.onUpload,
+	}
+}
+
+// Handler оборачивает обработчик обновлений: создаёт span на каждое обновление
+// и записывает длительность обработки. Запросы к API внутри обработчика становятся дочерними span.
+func (t *Telemetry) Handler(next maxbot.UpdateHandler) maxbot.UpdateHandler {
+	return func(ctx context.Context, update model.Update) {
+		attrs := map[string]any{
+			AttrUpdateType: string(update.UpdateType),
+			AttrChatID:     update.ChatID,
+			AttrUserID:     update.UserID,
+		}
+		ctx, span := startSpan(ctx, "update "+string(update.UpdateType), attrs, t.now())
+
+		defer func() {
+			span.End = t.now()
+			t.spans.ExportSpan(*span)
+			t.metrics.RecordHistogram(MetricHandlerDuration, span.Duration().Seconds(), map[string]string{
+				"update_type": string(update.UpdateType),
+			})
+		}()
+
+		next(ctx, update)
+	}
+}
+
+func (t *Telemetry) onRequest(ctx context.Context, req maxbot.RequestInfo) context.Context {
+	attrs := map[string]any{
+		AttrMethod:   req.Method,
+		AttrEndpoint: req.Endpoint,
+	}
+	if req.ChatID != 0 {
+		attrs[AttrChatID] = req.ChatID
+	}
+
+	ctx, _ = startSpan(ctx, req.Method+" "+req.Endpoint, attrs, t.now())
+
+	return ctx
+}
+
+func (t *Telemetry) onResponse(ctx context.Context, req maxbot.RequestInfo, res maxbot.ResponseInfo) {
+	labels := map[string]string{
+		"method":   req.Method,
+		"endpoint": req.Endpoint,
+		"status":   strconv.Itoa(res.StatusCode),
+	}
+	t.metrics.AddCounter(MetricRequests, 1, labels)
+	t.metrics.RecordHistogram(MetricRequestDuration, res.Duration.Seconds(), map[string]string{
+		"method":   req.Method,
+		"endpoint": req.Endpoint,
+	})
+
+	span, ok := SpanFromContext(ctx)
+	if !ok {
+		return
+	}
+
+	span.End = t.now()
+	span.Err = res.Err
+	span.Attributes[AttrStatusCode] = res.StatusCode
+	t.spans.ExportSpan(*span)
+}
+
+func (t *Telemetry) onRetry(_ context.Context, req maxbot.RequestInfo, _ int, _ error) {
+	t.metrics.AddCounter(MetricRetries, 1, map[string]string{
+		"method":   req.Method,
+		"endpoint": req.Endpoint,
+	})
+}
+
+func (t *Telemetry) onUpload(_ context.Context, uploadType model.UploadType, size int64, err error) {
+	status := "success"
+	if err != nil {
+		status = "error"
+	}
+	t.metrics.AddCounter(MetricUploads, 1, map[string]string{
+		"type":   string(uploadType),
+		"status": status,
+	})
+
+	if err == nil {
+		t.metrics.AddCounter(MetricUploadBytes, float64(size), map[string]string{
+			"type": string(uploadType),
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package telemetry
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	maxbot "github.com/max-messenger/max-bot-api-client-go/v2"
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+func TestTelemetry_HandlerAndRequests(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/chats/42" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"code":"not.found","message":"chat not found"}`))
+
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"user_id":1,"first_name":"bot"}`))
+	}))
+	defer srv.Close()
+
+	exporter := NewInMemoryExporter()
+	metrics := NewInMemoryMetrics()
+	tel := New(WithSpanExporter(exporter), WithMetricExporter(metrics))
+
+	api, err := maxbot.NewApi("token", maxbot.WithBaseURL(srv.URL), maxbot.WithHook(tel.Hook()))
+	require.NoError(t, err)
+
+	handler := tel.Handler(func(ctx context.Context, update model.Update) {
+		_, err := api.Bots.GetMyInfo(ctx)
+		assert.NoError(t, err)
+
+		_, err = api.Chats.GetChat(ctx, 42)
+		assert.Error(t, err)
+	})
+
+	handler(context.Background(), model.Update{UpdateType: model.UpdateMessageCreated, ChatID: 42, UserID: 7})
+
+	spans := exporter.Spans()
+	require.Len(t, spans, 3)
+
+	me, chat, update := spans[0], spans[1], spans[2]
+	assert.Equal(t, "update message_created", update.Name)
+	assert.Equal(t, "message_created", update.Attributes[AttrUpdateType])
+	assert.Empty(t, update.ParentID)
+
+	assert.Equal(t, "GET /me", me.Name)
+	assert.Equal(t, update.TraceID, me.TraceID)
+	assert.Equal(t, update.SpanID, me.ParentID)
+	assert.Equal(t, http.StatusOK, me.Attributes[AttrStatusCode])
+	assert.NoError(t, me.Err)
+
+	assert.Equal(t, "GET /chats/{id}", chat.Name)
+	assert.Equal(t, int64(42), chat.Attributes[AttrChatID])
+	assert.Equal(t, http.StatusNotFound, chat.Attributes[AttrStatusCode])
+	assert.Error(t, chat.Err)
+
+	assert.Equal(t, float64(1), metrics.Counter(MetricRequests, map[string]string{
+		"method": http.MethodGet, "endpoint": "/me", "status": "200",
+	}))
+	assert.Equal(t, float64(1), metrics.Counter(MetricRequests, map[string]string{
+		"method": http.MethodGet, "endpoint": "/chats/{id}", "status": "404",
+	}))
+	assert.Len(t, metrics.Histogram(MetricHandlerDuration, map[string]string{"update_type": "message_created"}), 1)
+}
+
+func TestTelemetry_Retries(t *testing.T) {
+	var count int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		if count < 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"code":"attachment.not.ready","message":"not ready"}`))
+
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		_, _ = w.Write([]byte(`{"message":{}}`))
+	}))
+	defer srv.Close()
+
+	metrics := NewInMemoryMetrics()
+	tel := New(WithMetricExporter(metrics))
+
+	api, err := maxbot.NewApi("token", maxbot.WithBaseURL(srv.URL), maxbot.WithHook(tel.Hook()))
+	require.NoError(t, err)
+
+	_, err = api.Messages.Send(context.Background(), maxbot.NewMessage().SetChat(1).SetText("hi"))
+	require.NoError(t, err)
+
+	assert.Equal(t, float64(1), metrics.Counter(MetricRetries, map[string]string{
+		"method": http.MethodPost, "endpoint": "/messages",
+	}))
+}
+
+func TestTelemetry_Upload(t *testing.T) {
+	metrics := NewInMemoryMetrics()
+	hook := New(WithMetricExporter(metrics)).Hook()
+
+	hook.OnUpload(context.Background(), model.UploadImage, 100, nil)
+	hook.OnUpload(context.Background(), model.UploadImage, 50, nil)
+	hook.OnUpload(context.Background(), model.UploadVideo, 10, io.ErrUnexpectedEOF)
+
+	assert.Equal(t, float64(2), metrics.Counter(MetricUploads, map[string]string{"type": "image", "status": "success"}))
+	assert.Equal(t, float64(1), metrics.Counter(MetricUploads, map[string]string{"type": "video", "status": "error"}))
+	assert.Equal(t, float64(150), metrics.Counter(MetricUploadBytes, map[string]string{"type": "image"}))
+	assert.Equal(t, float64(0), metrics.Counter(MetricUploadBytes, map[string]string{"type": "video"}))
+}
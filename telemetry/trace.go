@@ -0,0 +1,102 @@
+package telemetry
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"sync"
+	"time"
+)
+
+// Span описывает одну операцию: запрос к API или обработку обновления.
+type Span struct {
+	TraceID    string
+	SpanID     string
+	ParentID   string
+	Name       string
+	Start      time.Time
+	End        time.Time
+	Attributes map[string]any
+	Err        error
+}
+
+func (s Span) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// SpanExporter получает завершённые span.
+type SpanExporter interface {
+	ExportSpan(span Span)
+}
+
+// InMemoryExporter хранит span в памяти. Подходит для тестов.
+type InMemoryExporter struct {
+	mu    sync.Mutex
+	spans []Span
+}
+
+func NewInMemoryExporter() *InMemoryExporter {
+	return &InMemoryExporter{}
+}
+
+func (e *InMemoryExporter) ExportSpan(span Span) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = append(e.spans, span)
+}
+
+func (e *InMemoryExporter) Spans() []Span {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	res := make([]Span, len(e.spans))
+	copy(res, e.spans)
+
+	return res
+}
+
+func (e *InMemoryExporter) Reset() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.spans = nil
+}
+
+type noopSpanExporter struct{}
+
+func (noopSpanExporter) ExportSpan(Span) {}
+
+type spanKey struct{}
+
+// SpanFromContext возвращает активный span из контекста.
+func SpanFromContext(ctx context.Context) (*Span, bool) {
+	span, ok := ctx.Value(spanKey{}).(*Span)
+
+	return span, ok
+}
+
+func startSpan(ctx context.Context, name string, attrs map[string]any, now time.Time) (context.Context, *Span) {
+	span := &Span{
+		SpanID:     newID(8),
+		Name:       name,
+		Start:      now,
+		Attributes: attrs,
+	}
+
+	if parent, ok := SpanFromContext(ctx); ok {
+		span.TraceID = parent.TraceID
+		span.ParentID = parent.SpanID
+	} else {
+		span.TraceID = newID(16)
+	}
+
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func newID(size int) string {
+	b := make([]byte, size)
+	_, _ = rand.Read(b)
+
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,238 @@
+// Package templates формирует сообщения из шаблонов text/template с выбором языка пользователя.
+//
+// Шаблоны загружаются из fs.FS: файл <locale>/<name>.tmpl задаёт шаблон name для языка locale,
+// файлы в корне относятся к языку по умолчанию. Кроме текста шаблон может задать формат
+// и клавиатуру с помощью функций:
+//
+//	{{format "html"}}Привет, <b>{{escape .Name}}</b>!
+//	{{row}}{{callback "Начать" "start"}}{{link "Справка" "https://dev.max.ru"}}
+//
+//	set, err := templates.Load(os.DirFS("templates"))
+//	msg, err := set.RenderUpdate(update, "welcome", data)
+//	_, err = api.Messages.Send(ctx, msg.SetChat(update.ChatID))
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"text/template"
+
+	maxbot "github.com/max-messenger/max-bot-api-client-go/v2"
+	"github.com/max-messenger/max-bot-api-client-go/v2/i18n"
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+	"github.com/max-messenger/max-bot-api-client-go/v2/text"
+)
+
+const (
+	defaultLocale = "ru"
+	extension     = ".tmpl"
+)
+
+type Set struct {
+	defaultLocale string
+	format        model.TextFormat
+	funcs         template.FuncMap
+	// locales шаблоны каждого языка, связанные между собой для {{template "name"}}
+	locales map[string]*template.Template
+}
+
+type Option func(s *Set)
+
+// WithDefaultLocale задаёт язык, шаблоны которого используются, если для языка пользователя шаблона нет.
+func WithDefaultLocale(locale string) Option {
+	return func(s *Set) {
+		s.defaultLocale = i18n.NormalizeLocale(locale)
+	}
+}
+
+// WithFormat задаёт формат текста, если шаблон не вызывает format.
+func WithFormat(format model.TextFormat) Option {
+	return func(s *Set) {
+		s.format = format
+	}
+}
+
+// WithFuncs добавляет функции, доступные в шаблонах.
+func WithFuncs(funcs template.FuncMap) Option {
+	return func(s *Set) {
+		for name, fn := range funcs {
+			s.funcs[name] = fn
+		}
+	}
+}
+
+// Load загружает все файлы *.tmpl из fsys.
+func Load(fsys fs.FS, opts ...Option) (*Set, error) {
+	s := &Set{
+		defaultLocale: defaultLocale,
+		funcs:         template.FuncMap{},
+		locales:       map[string]*template.Template{},
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || path.Ext(p) != extension {
+			return err
+		}
+
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return fmt.Errorf("read template %s: %w", p, err)
+		}
+
+		locale := s.defaultLocale
+		if dir := path.Dir(p); dir != "." {
+			locale = i18n.NormalizeLocale(path.Base(dir))
+		}
+
+		root, ok := s.locales[locale]
+		if !ok {
+			root = template.New(locale).Funcs((&renderState{}).funcs()).Funcs(s.funcs)
+			s.locales[locale] = root
+		}
+
+		name := strings.TrimSuffix(path.Base(p), extension)
+		if _, err = root.New(name).Parse(string(data)); err != nil {
+			return fmt.Errorf("parse template %s: %w", p, err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Render формирует сообщение по шаблону name для языка locale. Если шаблона для locale нет,
+// используется шаблон для основного языка (ru для ru-RU), затем для языка по умолчанию.
+func (s *Set) Render(locale, name string, data any) (*maxbot.Message, error) {
+	root, locale, ok := s.lookup(locale, name)
+	if !ok {
+		return nil, fmt.Errorf("templates: template %q not found", name)
+	}
+
+	st := &renderState{format: s.format, locale: locale}
+	tmpl, err := root.Clone()
+	if err != nil {
+		return nil, fmt.Errorf("clone template %q: %w", name, err)
+	}
+	tmpl.Funcs(st.funcs())
+
+	var buf bytes.Buffer
+	if err = tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return nil, fmt.Errorf("render template %q: %w", name, err)
+	}
+
+	msg := maxbot.NewMessage().
+		SetText(strings.TrimSpace(buf.String())).
+		SetFormat(st.format)
+	if st.keyboard != nil {
+		msg.AddKeyboard(st.keyboard)
+	}
+
+	return msg, nil
+}
+
+// RenderUpdate формирует сообщение на языке пользователя из update.UserLocale.
+func (s *Set) RenderUpdate(update model.Update, name string, data any) (*maxbot.Message, error) {
+	return s.Render(update.UserLocale, name, data)
+}
+
+// Locales возвращает языки, для которых загружены шаблоны.
+func (s *Set) Locales() []string {
+	locales := make([]string, 0, len(s.locales))
+	for locale := range s.locales {
+		locales = append(locales, locale)
+	}
+
+	return locales
+}
+
+func (s *Set) lookup(locale, name string) (*template.Template, string, bool) {
+	for _, candidate := range i18n.FallbackLocales(locale, s.defaultLocale) {
+		if root, ok := s.locales[candidate]; ok && root.Lookup(name) != nil {
+			return root, candidate, true
+		}
+	}
+
+	return nil, "", false
+}
+
+// renderState накапливает формат и клавиатуру во время выполнения шаблона.
+type renderState struct {
+	format   model.TextFormat
+	locale   string
+	keyboard *model.Keyboard
+	row      *model.KeyboardRow
+}
+
+func (st *renderState) funcs() template.FuncMap {
+	return template.FuncMap{
+		"format": func(format string) string {
+			st.format = model.TextFormat(format)
+
+			return ""
+		},
+		"escape": func(s any) string {
+			if st.format == "" {
+				return fmt.Sprint(s)
+			}
+
+			return text.Escape(fmt.Sprint(s), st.format)
+		},
+		"locale": func() string {
+			return st.locale
+		},
+		"row": func() string {
+			if st.keyboard == nil {
+				st.keyboard = model.NewKeyboard()
+			}
+			st.row = st.keyboard.AddRow()
+
+			return ""
+		},
+		"callback": func(label, payload string) string {
+			st.currentRow().AddCallBack(label, payload)
+
+			return ""
+		},
+		"link": func(label, url string) string {
+			st.currentRow().AddLink(label, url)
+
+			return ""
+		},
+		"message": func(label string) string {
+			st.currentRow().AddMessage(label)
+
+			return ""
+		},
+		"contact": func(label string) string {
+			st.currentRow().AddContact(label)
+
+			return ""
+		},
+		"geo": func(label string) string {
+			st.currentRow().AddGeoLocation(label, false)
+
+			return ""
+		},
+	}
+}
+
+func (st *renderState) currentRow() *model.KeyboardRow {
+	if st.row == nil {
+		if st.keyboard == nil {
+			st.keyboard = model.NewKeyboard()
+		}
+		st.row = st.keyboard.AddRow()
+	}
+
+	return st.row
+}
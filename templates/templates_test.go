@@ -0,0 +1,94 @@
+package templates
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+func testFS() fstest.MapFS {
+	return fstest.MapFS{
+		"ru/welcome.tmpl": {Data: []byte(`{{format "html"}}Привет, <b>{{escape .Name}}</b>! {{template "footer"}}
+{{row}}{{callback "Начать" "start"}}{{link "Справка" "https://dev.max.ru"}}
+{{row}}{{contact "Поделиться контактом"}}`)},
+		"ru/footer.tmpl":     {Data: []byte(`Ваш язык: {{locale}}`)},
+		"en/welcome.tmpl":    {Data: []byte(`{{format "markdown"}}Hello, **{{escape .Name}}**!`)},
+		"en-gb/goodbye.tmpl": {Data: []byte(`Cheerio`)},
+		"goodbye.tmpl":       {Data: []byte(`Пока`)},
+		"readme.txt":         {Data: []byte(`not a template`)},
+	}
+}
+
+func TestSet_Render(t *testing.T) {
+	set, err := Load(testFS())
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"ru", "en", "en-gb"}, set.Locales())
+
+	msg, err := set.Render("ru", "welcome", map[string]string{"Name": "<Иван>"})
+	require.NoError(t, err)
+
+	body := msg.MessageBody()
+	assert.Equal(t, "Привет, <b>&lt;Иван&gt;</b>! Ваш язык: ru", body.Text)
+	assert.Equal(t, model.FormatHTML, body.Format)
+	require.Len(t, body.Attachments, 1)
+
+	buttons := body.Attachments[0].Payload.Buttons
+	require.Len(t, buttons, 2)
+	assert.Equal(t, "start", buttons[0][0].Payload)
+	assert.Equal(t, model.ButtonLink, buttons[0][1].Type)
+	assert.Equal(t, model.ButtonRequestContact, buttons[1][0].Type)
+}
+
+func TestSet_RenderFallback(t *testing.T) {
+	set, err := Load(testFS())
+	require.NoError(t, err)
+
+	tests := []struct {
+		locale   string
+		name     string
+		expected string
+	}{
+		{"en", "welcome", `Hello, **\_bob\_**!`},
+		{"en-US", "welcome", `Hello, **\_bob\_**!`},
+		{"en_GB", "goodbye", "Cheerio"},
+		{"en-US", "goodbye", "Пока"},
+		{"de", "welcome", "Привет, <b>_bob_</b>! Ваш язык: ru"},
+		{"", "goodbye", "Пока"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.locale+"/"+tt.name, func(t *testing.T) {
+			msg, err := set.RenderUpdate(model.Update{UserLocale: tt.locale}, tt.name, map[string]string{"Name": "_bob_"})
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, msg.MessageBody().Text)
+		})
+	}
+
+	_, err = set.Render("ru", "missing", nil)
+	assert.ErrorContains(t, err, `template "missing" not found`)
+}
+
+func TestLoad_Options(t *testing.T) {
+	fsys := fstest.MapFS{
+		"en/hello.tmpl": {Data: []byte(`{{upper .}} {{escape "*"}}`)},
+	}
+
+	set, err := Load(fsys,
+		WithDefaultLocale("en"),
+		WithFormat(model.FormatMarkdown),
+		WithFuncs(map[string]any{"upper": func(s string) string { return s + "!" }}),
+	)
+	require.NoError(t, err)
+
+	msg, err := set.Render("fr", "hello", "hi")
+	require.NoError(t, err)
+	assert.Equal(t, `hi! \*`, msg.MessageBody().Text)
+	assert.Equal(t, model.FormatMarkdown, msg.MessageBody().Format)
+
+	_, err = Load(fstest.MapFS{"bad.tmpl": {Data: []byte(`{{`)}})
+	assert.ErrorContains(t, err, "parse template bad.tmpl")
+}
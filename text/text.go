@@ -0,0 +1,248 @@
+// Package text собирает текст сообщения с разметкой Markdown или HTML.
+// Значения, переданные в методы Builder, экранируются, поэтому в них можно подставлять
+// имена пользователей и другой произвольный текст.
+//
+//	t := text.Markdown().Bold("Привет, ").Mention(user.Name, user.UserID).Text("!")
+//	msg := maxbot.NewMessage().SetChat(chatID).SetFormattedText(t)
+package text
+
+import (
+	"html"
+	"strconv"
+	"strings"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+const mentionURL = "max://user/"
+
+type Builder struct {
+	format model.TextFormat
+	b      strings.Builder
+}
+
+// New создаёт построитель для format: model.FormatMarkdown или model.FormatHTML.
+func New(format model.TextFormat) *Builder {
+	if format != model.FormatHTML {
+		format = model.FormatMarkdown
+	}
+
+	return &Builder{format: format}
+}
+
+func Markdown() *Builder {
+	return New(model.FormatMarkdown)
+}
+
+func HTML() *Builder {
+	return New(model.FormatHTML)
+}
+
+// Format возвращает формат текста для Message.SetFormat.
+func (t *Builder) Format() model.TextFormat {
+	return t.format
+}
+
+// String возвращает текст с разметкой.
+func (t *Builder) String() string {
+	return t.b.String()
+}
+
+// Len возвращает длину текста с разметкой в символах.
+func (t *Builder) Len() int {
+	return len([]rune(t.b.String()))
+}
+
+// Text добавляет текст без оформления.
+func (t *Builder) Text(s string) *Builder {
+	t.b.WriteString(t.escape(s))
+
+	return t
+}
+
+// Raw добавляет текст без экранирования. Разметка должна соответствовать Format.
+func (t *Builder) Raw(s string) *Builder {
+	t.b.WriteString(s)
+
+	return t
+}
+
+// Line добавляет перевод строки.
+func (t *Builder) Line() *Builder {
+	t.b.WriteString("\n")
+
+	return t
+}
+
+func (t *Builder) Bold(s string) *Builder {
+	return t.wrap(s, "**", "b")
+}
+
+func (t *Builder) Italic(s string) *Builder {
+	return t.wrap(s, "_", "i")
+}
+
+func (t *Builder) Strike(s string) *Builder {
+	return t.wrap(s, "~~", "s")
+}
+
+func (t *Builder) Underline(s string) *Builder {
+	return t.wrap(s, "++", "u")
+}
+
+// Highlight выделяет текст цветом.
+func (t *Builder) Highlight(s string) *Builder {
+	return t.wrap(s, "^^", "mark")
+}
+
+// Code добавляет моноширинный текст в строке.
+func (t *Builder) Code(s string) *Builder {
+	if t.format == model.FormatHTML {
+		t.b.WriteString("<code>" + html.EscapeString(s) + "</code>")
+
+		return t
+	}
+
+	fence := codeFence(s, 1)
+	if strings.HasPrefix(s, "`") || strings.HasSuffix(s, "`") {
+		s = " " + s + " "
+	}
+	t.b.WriteString(fence + s + fence)
+
+	return t
+}
+
+// Pre добавляет блок моноширинного текста.
+func (t *Builder) Pre(s string) *Builder {
+	t.startLine()
+	if t.format == model.FormatHTML {
+		t.b.WriteString("<pre>" + html.EscapeString(s) + "</pre>\n")
+
+		return t
+	}
+
+	fence := codeFence(s, 3)
+	t.b.WriteString(fence + "\n" + s + "\n" + fence + "\n")
+
+	return t
+}
+
+// Link добавляет ссылку с текстом s.
+func (t *Builder) Link(s, url string) *Builder {
+	if t.format == model.FormatHTML {
+		t.b.WriteString(`<a href="` + html.EscapeString(url) + `">` + html.EscapeString(s) + "</a>")
+
+		return t
+	}
+
+	t.b.WriteString("[" + escapeMarkdown(s) + "](" + markdownURLEscaper.Replace(url) + ")")
+
+	return t
+}
+
+// Mention добавляет упоминание пользователя без username.
+func (t *Builder) Mention(name string, userID int64) *Builder {
+	return t.Link(name, mentionURL+strconv.FormatInt(userID, 10))
+}
+
+// Heading добавляет заголовок на отдельной строке.
+func (t *Builder) Heading(s string) *Builder {
+	t.startLine()
+	if t.format == model.FormatHTML {
+		t.b.WriteString("<h1>" + html.EscapeString(s) + "</h1>\n")
+
+		return t
+	}
+
+	t.b.WriteString("# " + escapeMarkdown(singleLine(s)) + "\n")
+
+	return t
+}
+
+// Quote добавляет цитату на отдельных строках.
+func (t *Builder) Quote(s string) *Builder {
+	t.startLine()
+	if t.format == model.FormatHTML {
+		t.b.WriteString("<blockquote>" + html.EscapeString(s) + "</blockquote>\n")
+
+		return t
+	}
+
+	for _, line := range strings.Split(s, "\n") {
+		t.b.WriteString("> " + escapeMarkdown(line) + "\n")
+	}
+
+	return t
+}
+
+func (t *Builder) wrap(s, marker, tag string) *Builder {
+	if s == "" {
+		return t
+	}
+
+	if t.format == model.FormatHTML {
+		t.b.WriteString("<" + tag + ">" + html.EscapeString(s) + "</" + tag + ">")
+
+		return t
+	}
+
+	t.b.WriteString(marker + escapeMarkdown(s) + marker)
+
+	return t
+}
+
+func (t *Builder) escape(s string) string {
+	if t.format == model.FormatHTML {
+		return html.EscapeString(s)
+	}
+
+	return escapeMarkdown(s)
+}
+
+// startLine переносит строку, если блочный элемент добавляется не с начала строки.
+func (t *Builder) startLine() {
+	if t.b.Len() > 0 && !strings.HasSuffix(t.b.String(), "\n") {
+		t.b.WriteString("\n")
+	}
+}
+
+var (
+	markdownEscaper = strings.NewReplacer(
+		`\`, `\\`, "*", `\*`, "_", `\_`, "~", `\~`, "+", `\+`, "^", `\^`, "`", "\\`",
+		"[", `\[`, "]", `\]`, "(", `\(`, ")", `\)`, "#", `\#`, ">", `\>`,
+	)
+	markdownURLEscaper = strings.NewReplacer(" ", "%20", "(", "%28", ")", "%29")
+)
+
+// Escape экранирует s для format.
+func Escape(s string, format model.TextFormat) string {
+	if format == model.FormatHTML {
+		return html.EscapeString(s)
+	}
+
+	return escapeMarkdown(s)
+}
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
+
+// codeFence возвращает последовательность обратных кавычек длиннее любой внутри s.
+func codeFence(s string, minLen int) string {
+	longest, run := 0, 0
+	for _, r := range s {
+		if r == '`' {
+			run++
+			longest = max(longest, run)
+
+			continue
+		}
+		run = 0
+	}
+
+	return strings.Repeat("`", max(minLen, longest+1))
+}
+
+func singleLine(s string) string {
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,69 @@
+package text
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+func TestBuilder_Markdown(t *testing.T) {
+	tests := []struct {
+		name     string
+		build    func(*Builder) *Builder
+		expected string
+	}{
+		{"text is escaped", func(b *Builder) *Builder { return b.Text("2*2 = [4]_") }, `2\*2 = \[4\]\_`},
+		{"bold", func(b *Builder) *Builder { return b.Bold("a**b") }, `**a\*\*b**`},
+		{"italic", func(b *Builder) *Builder { return b.Italic("x") }, "_x_"},
+		{"strike", func(b *Builder) *Builder { return b.Strike("x") }, "~~x~~"},
+		{"underline", func(b *Builder) *Builder { return b.Underline("x") }, "++x++"},
+		{"highlight", func(b *Builder) *Builder { return b.Highlight("x") }, "^^x^^"},
+		{"empty style is skipped", func(b *Builder) *Builder { return b.Bold("") }, ""},
+		{"code", func(b *Builder) *Builder { return b.Code("a*b") }, "`a*b`"},
+		{"code with backticks", func(b *Builder) *Builder { return b.Code("`x`") }, "`` `x` ``"},
+		{"pre", func(b *Builder) *Builder { return b.Text("a").Pre("x := ```") }, "a\n````\nx := ```\n````\n"},
+		{"link", func(b *Builder) *Builder { return b.Link("docs [v2]", "https://dev.max.ru/a (b)") }, `[docs \[v2\]](https://dev.max.ru/a%20%28b%29)`},
+		{"mention", func(b *Builder) *Builder { return b.Mention("*Ann*", 42) }, `[\*Ann\*](max://user/42)`},
+		{"heading", func(b *Builder) *Builder { return b.Text("a").Heading("#1\nnews") }, "a\n# \\#1 news\n"},
+		{"quote", func(b *Builder) *Builder { return b.Quote("one\n> two") }, "> one\n> \\> two\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := tt.build(Markdown())
+			assert.Equal(t, tt.expected, b.String())
+			assert.Equal(t, model.FormatMarkdown, b.Format())
+		})
+	}
+}
+
+func TestBuilder_HTML(t *testing.T) {
+	b := HTML().
+		Heading("<News>").
+		Bold("Tom & Jerry").Text(" ").
+		Italic("i").Text(" ").
+		Strike("s").Text(" ").
+		Underline("u").Text(" ").
+		Highlight("m").Text(" ").
+		Code("<x>").Line().
+		Link(`"quoted"`, `https://dev.max.ru/?a=1&b=2`).Text(", ").
+		Mention("Ann", 42).
+		Quote("q").
+		Pre("if a < b {}")
+
+	expected := "<h1>&lt;News&gt;</h1>\n" +
+		"<b>Tom &amp; Jerry</b> <i>i</i> <s>s</s> <u>u</u> <mark>m</mark> <code>&lt;x&gt;</code>\n" +
+		`<a href="https://dev.max.ru/?a=1&amp;b=2">&#34;quoted&#34;</a>, <a href="max://user/42">Ann</a>` + "\n" +
+		"<blockquote>q</blockquote>\n" +
+		"<pre>if a &lt; b {}</pre>\n"
+
+	assert.Equal(t, expected, b.String())
+	assert.Equal(t, model.FormatHTML, b.Format())
+}
+
+func TestEscape(t *testing.T) {
+	assert.Equal(t, `\_name\_`, Escape("_name_", model.FormatMarkdown))
+	assert.Equal(t, "&lt;b&gt;", Escape("<b>", model.FormatHTML))
+}
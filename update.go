@@ -52,6 +52,7 @@ func (u updateRaw) FromRaw() model.Update {
 				Text:        u.Message.Body.Text,
 				Attachments: u.Message.Body.Attachments,
 			},
+			Link: u.Message.Link,
 		}
 		update.Callback = &u.Callback
 
@@ -1,15 +1,22 @@
 package maxbot
 
 import (
+	"bufio"
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"mime/multipart"
 	"net/http"
+	"net/textproto"
 	"net/url"
+	"os"
+	"path"
 	"path/filepath"
+	"strings"
 
 	"github.com/max-messenger/max-bot-api-client-go/v2/model"
 )
@@ -24,14 +31,62 @@ func newUpload(cli *client) *Upload {
 	}
 }
 
-func (u *Upload) Upload(ctx context.Context, uploadType model.UploadType, r io.Reader, name string, size int64) (token string, err error) {
+// Upload загружает файл и возвращает токен для отправки вложения. Отмена ctx прерывает отправку файла.
+// Параметры загрузки UploadOpt принимают UploadDetailed и остальные методы загрузки.
+func (u *Upload) Upload(ctx context.Context, uploadType model.UploadType, r io.Reader, name string, size int64) (string, error) {
+	return u.uploadToken(ctx, uploadType, r, name, size)
+}
+
+func (u *Upload) uploadToken(ctx context.Context, uploadType model.UploadType, r io.Reader, name string, size int64, opts ...UploadOpt) (string, error) {
+	res, err := u.UploadDetailed(ctx, uploadType, r, name, size, opts...)
+
+	return res.Token, err
+}
+
+// UploadDetailed загружает файл и возвращает все данные из ответа сервера: токены всех вариантов
+// изображения, токен аудио и видео и исходный ответ.
+func (u *Upload) UploadDetailed(ctx context.Context, uploadType model.UploadType, r io.Reader, name string, size int64, opts ...UploadOpt) (res model.UploadResult, err error) {
+	cache := u.client.uploadCache
+	if cache == nil {
+		return u.upload(ctx, uploadType, r, name, size, opts...)
+	}
+
+	key, ok, err := seekableCacheKey(uploadType, r)
+	if err != nil {
+		return
+	}
+	if !ok {
+		return u.upload(ctx, uploadType, r, name, size, opts...)
+	}
+
+	if cached, found := cache.get(ctx, key); found {
+		return cached, nil
+	}
+
+	res, err = u.upload(ctx, uploadType, r, name, size, opts...)
+	if err == nil {
+		cache.set(ctx, key, res)
+	}
+
+	return
+}
+
+func (u *Upload) upload(ctx context.Context, uploadType model.UploadType, r io.Reader, name string, size int64, opts ...UploadOpt) (res model.UploadResult, err error) {
+	o := newUploadOptions(opts)
+	defer func() { u.client.onUpload(ctx, uploadType, size, err) }()
+
 	endpoint, err := u.getUploadURL(ctx, uploadType)
 	if err != nil {
 		return
 	}
 
 	name = multipartFileName(name)
-	contentType, contentLength, boundary, err := multipartEnvelope(name, size)
+	// начало файла нужно для определения Content-Type, Peek не сдвигает позицию чтения
+	br := bufio.NewReaderSize(r, sniffLen)
+	head, _ := br.Peek(sniffLen)
+	partContentType := detectContentType(name, head)
+
+	contentType, contentLength, boundary, err := multipartEnvelope(name, partContentType, size)
 	if err != nil {
 		return
 	}
@@ -48,7 +103,17 @@ func (u *Upload) Upload(ctx context.Context, uploadType model.UploadType, r io.R
 	req.Header.Set("Content-Type", contentType)
 	req.ContentLength = contentLength
 
+	// горутина завершается, даже если запрос прерван до отправки всего файла:
+	// закрытие bodyReader прерывает запись в pipe
+	done := make(chan struct{})
+	defer func() {
+		_ = bodyReader.Close()
+		<-done
+	}()
+
 	go func() {
+		defer close(done)
+
 		writer := multipart.NewWriter(bodyWriter)
 		if gErr := writer.SetBoundary(boundary); gErr != nil {
 			_ = bodyWriter.CloseWithError(fmt.Errorf("set multipart boundary: %w", gErr))
@@ -56,13 +121,13 @@ func (u *Upload) Upload(ctx context.Context, uploadType model.UploadType, r io.R
 			return
 		}
 
-		fileWriter, gErr := writer.CreateFormFile(fieldData, name)
+		fileWriter, gErr := createFilePart(writer, name, partContentType)
 		if gErr != nil {
 			_ = bodyWriter.CloseWithError(fmt.Errorf("create form file: %w", gErr))
 
 			return
 		}
-		if _, gErr = io.Copy(fileWriter, r); gErr != nil {
+		if _, gErr = io.Copy(fileWriter, newProgressReader(br, size, o)); gErr != nil {
 			_ = bodyWriter.CloseWithError(fmt.Errorf("copy file data: %w", gErr))
 
 			return
@@ -87,15 +152,35 @@ func (u *Upload) Upload(ctx context.Context, uploadType model.UploadType, r io.R
 		return
 	}
 
+	return parseUploadResponse(uploadType, endpoint, resp.Body)
+}
+
+// parseUploadResponse разбирает ответ на загрузку файла.
+func parseUploadResponse(uploadType model.UploadType, endpoint model.UploadEndpoint, body io.Reader) (res model.UploadResult, err error) {
+	data, err := io.ReadAll(body)
+	if err != nil {
+		err = fmt.Errorf("read response body: %w", err)
+
+		return
+	}
+
+	res = model.UploadResult{
+		Type:          uploadType,
+		EndpointToken: endpoint.Token,
+	}
+	if len(bytes.TrimSpace(data)) > 0 {
+		res.Response = data
+	}
+
 	if uploadType == model.UploadAudio || uploadType == model.UploadVideo {
-		token = endpoint.Token
+		res.Token = endpoint.Token
 
 		return
 	}
 
 	if uploadType == model.UploadImage {
 		var photoTokens model.PhotoTokens
-		err = json.NewDecoder(resp.Body).Decode(&photoTokens)
+		err = json.Unmarshal(data, &photoTokens)
 		if err != nil {
 			err = fmt.Errorf("unmarshal response body: %w", err)
 
@@ -103,30 +188,152 @@ func (u *Upload) Upload(ctx context.Context, uploadType model.UploadType, r io.R
 		}
 		if len(photoTokens.Photos) == 0 {
 			err = fmt.Errorf("upload: photo response contains no tokens")
+
 			return
 		}
 
-		for _, pt := range photoTokens.Photos {
-			token = pt.Token
-			break
-		}
+		res.Photos = photoTokens.Photos
+		res.Token = res.Photos[res.PhotoKeys()[0]].Token
 
 		return
 	}
 
 	result := model.UploadedInfo{}
-	err = json.NewDecoder(resp.Body).Decode(&result)
+	err = json.Unmarshal(data, &result)
 	if err != nil {
 		err = fmt.Errorf("unmarshal response body: %w", err)
 
 		return
 	}
 
-	token = result.Token
+	res.Token = result.Token
+
+	return
+}
+
+// UploadAuto определяет тип загрузки по содержимому и имени файла и загружает файл.
+// Возвращает тип вложения для Message.AddAttachByToken.
+func (u *Upload) UploadAuto(ctx context.Context, r io.Reader, name string, size int64, opts ...UploadOpt) (token string, at model.AttachmentType, err error) {
+	head, r, err := peekHead(r)
+	if err != nil {
+		return
+	}
+
+	uploadType, _ := DetectUploadType(name, head)
+	at = uploadType.AttachmentType()
+	token, err = u.uploadToken(ctx, uploadType, r, name, size, opts...)
 
 	return
 }
 
+// peekHead читает начало файла для определения типа. Возвращает reader, с которого нужно
+// продолжить чтение: для io.ReadSeeker это исходный reader, что сохраняет возможность повторного чтения.
+func peekHead(r io.Reader) ([]byte, io.Reader, error) {
+	rs, ok := r.(io.ReadSeeker)
+	if !ok {
+		br := bufio.NewReaderSize(r, sniffLen)
+		head, _ := br.Peek(sniffLen)
+
+		return head, br, nil
+	}
+
+	pos, err := rs.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return nil, nil, fmt.Errorf("seek file: %w", err)
+	}
+
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(rs, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, nil, fmt.Errorf("read file: %w", err)
+	}
+
+	if _, err = rs.Seek(pos, io.SeekStart); err != nil {
+		return nil, nil, fmt.Errorf("seek file: %w", err)
+	}
+
+	return head[:n], rs, nil
+}
+
+// UploadFile загружает файл с диска. Размер и имя файла определяются автоматически.
+func (u *Upload) UploadFile(ctx context.Context, uploadType model.UploadType, filePath string, opts ...UploadOpt) (token string, err error) {
+	f, err := os.Open(filePath)
+	if err != nil {
+		err = fmt.Errorf("open file: %w", err)
+
+		return
+	}
+	defer func() { _ = f.Close() }()
+
+	info, err := f.Stat()
+	if err != nil {
+		err = fmt.Errorf("stat file: %w", err)
+
+		return
+	}
+
+	return u.uploadToken(ctx, uploadType, f, info.Name(), info.Size(), opts...)
+}
+
+func (u *Upload) UploadBytes(ctx context.Context, uploadType model.UploadType, name string, data []byte, opts ...UploadOpt) (string, error) {
+	return u.uploadToken(ctx, uploadType, bytes.NewReader(data), name, int64(len(data)), opts...)
+}
+
+// UploadFromURL скачивает файл по ссылке и загружает его. Если сервер не сообщает размер файла,
+// содержимое сохраняется во временный файл.
+func (u *Upload) UploadFromURL(ctx context.Context, uploadType model.UploadType, fileURL string, opts ...UploadOpt) (token string, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fileURL, nil)
+	if err != nil {
+		err = fmt.Errorf("failed to create request: %w", err)
+
+		return
+	}
+
+	resp, err := u.client.do(req)
+	if err != nil {
+		err = &NetworkError{Op: fmt.Sprintf("GET %s", fileURL), Err: err}
+
+		return
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if u.client.isNotOk(resp.StatusCode) {
+		err = fmt.Errorf("download %s: unexpected status %s", fileURL, resp.Status)
+
+		return
+	}
+
+	name := remoteFileName(resp)
+	if resp.ContentLength >= 0 {
+		return u.uploadToken(ctx, uploadType, resp.Body, name, resp.ContentLength, opts...)
+	}
+
+	tmp, err := os.CreateTemp("", "maxbot-upload-*")
+	if err != nil {
+		err = fmt.Errorf("create temp file: %w", err)
+
+		return
+	}
+	defer func() {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+	}()
+
+	size, err := io.Copy(tmp, resp.Body)
+	if err != nil {
+		err = fmt.Errorf("download %s: %w", fileURL, err)
+
+		return
+	}
+	if _, err = tmp.Seek(0, io.SeekStart); err != nil {
+		err = fmt.Errorf("seek temp file: %w", err)
+
+		return
+	}
+
+	return u.uploadToken(ctx, uploadType, tmp, name, size, opts...)
+}
+
 func (u *Upload) getUploadURL(ctx context.Context, uploadType model.UploadType) (res model.UploadEndpoint, err error) {
 	values := url.Values{}
 	values.Set(paramType, string(uploadType))
@@ -141,12 +348,12 @@ func (u *Upload) getUploadURL(ctx context.Context, uploadType model.UploadType)
 	return
 }
 
-func multipartEnvelope(fileName string, fileSize int64) (string, int64, string, error) {
+func multipartEnvelope(fileName, fileContentType string, fileSize int64) (string, int64, string, error) {
 	header := &bytes.Buffer{}
 	writer := multipart.NewWriter(header)
 	boundary := writer.Boundary()
 
-	if _, err := writer.CreateFormFile("data", fileName); err != nil {
+	if _, err := createFilePart(writer, fileName, fileContentType); err != nil {
 		return "", 0, "", err
 	}
 
@@ -158,6 +365,35 @@ func multipartEnvelope(fileName string, fileSize int64) (string, int64, string,
 	return contentType, int64(header.Len()) + fileSize, boundary, nil
 }
 
+// remoteFileName определяет имя файла по заголовку Content-Disposition или по пути в ссылке.
+func remoteFileName(resp *http.Response) string {
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil {
+		if name := params["filename"]; name != "" {
+			return name
+		}
+	}
+
+	if resp.Request != nil && resp.Request.URL != nil {
+		name := path.Base(resp.Request.URL.Path)
+		if name != "/" && name != "." {
+			return name
+		}
+	}
+
+	return ""
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+// createFilePart аналог multipart.Writer.CreateFormFile, который выставляет Content-Type файла.
+func createFilePart(writer *multipart.Writer, fileName, contentType string) (io.Writer, error) {
+	h := make(textproto.MIMEHeader)
+	h.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`, fieldData, quoteEscaper.Replace(fileName)))
+	h.Set("Content-Type", contentType)
+
+	return writer.CreatePart(h)
+}
+
 func multipartFileName(fileName string) string {
 	if fileName == "" {
 		return defaultFileName
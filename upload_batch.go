@@ -0,0 +1,89 @@
+package maxbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+const defaultUploadParallelism = 4
+
+// UploadItem файл для UploadBatch.
+type UploadItem struct {
+	Type   model.UploadType
+	Reader io.Reader
+	Name   string
+	Size   int64
+}
+
+// UploadBatchResult результат загрузки одного файла из UploadBatch.
+type UploadBatchResult struct {
+	Result model.UploadResult
+	Err    error
+}
+
+// UploadBatchResults результаты UploadBatch в порядке исходных файлов.
+type UploadBatchResults []UploadBatchResult
+
+// Err объединяет ошибки всех файлов. Возвращает nil, если все файлы загружены.
+func (r UploadBatchResults) Err() error {
+	var errs []error
+	for i, res := range r {
+		if res.Err != nil {
+			errs = append(errs, fmt.Errorf("item %d: %w", i, res.Err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Uploaded возвращает результаты успешно загруженных файлов для Message.AddUploads.
+func (r UploadBatchResults) Uploaded() []model.UploadResult {
+	uploaded := make([]model.UploadResult, 0, len(r))
+	for _, res := range r {
+		if res.Err == nil {
+			uploaded = append(uploaded, res.Result)
+		}
+	}
+
+	return uploaded
+}
+
+// UploadBatch загружает файлы параллельно, не более parallelism одновременно (<= 0 - значение по умолчанию 4).
+// Ошибка загрузки одного файла не прерывает остальные. opts применяются к каждому файлу.
+func (u *Upload) UploadBatch(ctx context.Context, items []UploadItem, parallelism int, opts ...UploadOpt) UploadBatchResults {
+	if parallelism <= 0 {
+		parallelism = defaultUploadParallelism
+	}
+
+	results := make(UploadBatchResults, len(items))
+	sem := make(chan struct{}, parallelism)
+
+	var wg sync.WaitGroup
+	for i, item := range items {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			results[i].Err = ctx.Err()
+
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+
+			results[i].Result, results[i].Err = u.UploadDetailed(ctx, item.Type, item.Reader, item.Name, item.Size, opts...)
+		}()
+	}
+	wg.Wait()
+
+	return results
+}
@@ -0,0 +1,91 @@
+package maxbot
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+func TestUpload_UploadBatch(t *testing.T) {
+	var active, maxActive int32
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case pathUpload:
+			_, _ = w.Write([]byte(`{"url":"` + srv.URL + `/upload-target"}`))
+		case "/upload-target":
+			n := atomic.AddInt32(&active, 1)
+			defer atomic.AddInt32(&active, -1)
+			for {
+				m := atomic.LoadInt32(&maxActive)
+				if n <= m || atomic.CompareAndSwapInt32(&maxActive, m, n) {
+					break
+				}
+			}
+			time.Sleep(10 * time.Millisecond)
+
+			file, header, err := r.FormFile(fieldData)
+			require.NoError(t, err)
+			data, _ := io.ReadAll(file)
+			if header.Filename == "bad.txt" {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"code":"bad.request","message":"bad file"}`))
+
+				return
+			}
+			_, _ = w.Write([]byte(`{"token":"token_` + string(data) + `"}`))
+		}
+	}))
+	defer srv.Close()
+
+	api, err := NewApi(testToken, WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	items := make([]UploadItem, 0, 6)
+	for _, name := range []string{"a", "b", "bad", "c", "d", "e"} {
+		items = append(items, UploadItem{
+			Type:   model.UploadFile,
+			Reader: bytes.NewReader([]byte(name)),
+			Name:   name + ".txt",
+			Size:   int64(len(name)),
+		})
+	}
+
+	results := api.ExtendedUpload().UploadBatch(context.Background(), items, 2)
+	require.Len(t, results, len(items))
+	assert.LessOrEqual(t, atomic.LoadInt32(&maxActive), int32(2))
+
+	assert.Equal(t, "token_a", results[0].Result.Token)
+	assert.Equal(t, "token_e", results[5].Result.Token)
+	assert.Error(t, results[2].Err)
+	assert.ErrorContains(t, results.Err(), "item 2")
+
+	msg := NewMessage().AddUploads(results.Uploaded()...)
+	body := msg.MessageBody()
+	require.Len(t, body.Attachments, 5)
+	assert.Equal(t, model.AttachFile, body.Attachments[3].Type)
+	assert.Equal(t, "token_d", body.Attachments[3].Payload.Token)
+}
+
+func TestUpload_UploadBatch_ContextCancelled(t *testing.T) {
+	api, err := NewApi(testToken)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	results := api.ExtendedUpload().UploadBatch(ctx, []UploadItem{{Type: model.UploadFile, Reader: bytes.NewReader(nil)}}, 1)
+	assert.ErrorIs(t, results.Err(), context.Canceled)
+	assert.Empty(t, results.Uploaded())
+}
@@ -0,0 +1,182 @@
+package maxbot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+const (
+	uploadCachePrefix      = "maxbot:upload:"
+	uploadCacheTokenPrefix = "maxbot:upload-token:"
+)
+
+// UploadCache хранилище результатов загрузки файлов. Реализация должна быть безопасна
+// для конкурентного использования.
+type UploadCache interface {
+	Get(ctx context.Context, key string) (string, bool)
+	Set(ctx context.Context, key, value string, ttl time.Duration)
+	Delete(ctx context.Context, key string)
+}
+
+// WithUploadCache включает кеширование токенов загруженных файлов по хешу содержимого и типу загрузки.
+// Повторная загрузка того же файла возвращает сохранённый токен. Кешируются только файлы,
+// которые можно прочитать повторно: источники с io.Seeker или io.ReaderAt.
+// ttl <= 0 означает хранение без срока.
+func WithUploadCache(cache UploadCache, ttl time.Duration) Opt {
+	return func(c *client) error {
+		c.uploadCache = &uploadCache{
+			store: cache,
+			ttl:   ttl,
+		}
+
+		return nil
+	}
+}
+
+type uploadCache struct {
+	store UploadCache
+	ttl   time.Duration
+}
+
+// get возвращает сохранённый результат загрузки. Результат хранится в JSON.
+func (c *uploadCache) get(ctx context.Context, key string) (model.UploadResult, bool) {
+	var res model.UploadResult
+
+	value, ok := c.store.Get(ctx, uploadCachePrefix+key)
+	if !ok {
+		return res, false
+	}
+
+	if err := json.Unmarshal([]byte(value), &res); err != nil || res.Token == "" {
+		return res, false
+	}
+
+	return res, true
+}
+
+func (c *uploadCache) set(ctx context.Context, key string, res model.UploadResult) {
+	value, err := json.Marshal(res)
+	if err != nil {
+		return
+	}
+
+	c.store.Set(ctx, uploadCachePrefix+key, string(value), c.ttl)
+	c.store.Set(ctx, uploadCacheTokenPrefix+res.Token, key, c.ttl)
+}
+
+// invalidate удаляет запись, по которой был получен token.
+func (c *uploadCache) invalidate(ctx context.Context, token string) {
+	key, ok := c.store.Get(ctx, uploadCacheTokenPrefix+token)
+	if !ok {
+		return
+	}
+
+	c.store.Delete(ctx, uploadCachePrefix+key)
+	c.store.Delete(ctx, uploadCacheTokenPrefix+token)
+}
+
+// invalidateMessage удаляет из кеша токены вложений сообщения.
+func (c *uploadCache) invalidateMessage(ctx context.Context, body model.NewMessageBody) {
+	for _, attach := range body.Attachments {
+		if attach.Payload.Token != "" {
+			c.invalidate(ctx, attach.Payload.Token)
+		}
+	}
+}
+
+// uploadCacheKey вычисляет ключ по содержимому r и типу загрузки.
+func uploadCacheKey(uploadType model.UploadType, r io.Reader) (string, error) {
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", fmt.Errorf("hash file: %w", err)
+	}
+
+	return string(uploadType) + ":" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// seekableCacheKey вычисляет ключ для io.ReadSeeker и возвращает позицию чтения на место.
+func seekableCacheKey(uploadType model.UploadType, r io.Reader) (string, bool, error) {
+	rs, ok := r.(io.ReadSeeker)
+	if !ok {
+		return "", false, nil
+	}
+
+	pos, err := rs.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", false, nil
+	}
+
+	key, err := uploadCacheKey(uploadType, rs)
+	if err != nil {
+		return "", false, err
+	}
+
+	if _, err = rs.Seek(pos, io.SeekStart); err != nil {
+		return "", false, fmt.Errorf("seek file: %w", err)
+	}
+
+	return key, true, nil
+}
+
+// MemoryUploadCache хранит токены в памяти процесса.
+type MemoryUploadCache struct {
+	mu      sync.Mutex
+	now     func() time.Time
+	entries map[string]memoryCacheEntry
+}
+
+type memoryCacheEntry struct {
+	value     string
+	expiresAt time.Time
+}
+
+func NewMemoryUploadCache() *MemoryUploadCache {
+	return &MemoryUploadCache{
+		now:     time.Now,
+		entries: make(map[string]memoryCacheEntry),
+	}
+}
+
+func (c *MemoryUploadCache) Get(_ context.Context, key string) (string, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.entries[key]
+	if !ok {
+		return "", false
+	}
+
+	if !entry.expiresAt.IsZero() && !c.now().Before(entry.expiresAt) {
+		delete(c.entries, key)
+
+		return "", false
+	}
+
+	return entry.value, true
+}
+
+func (c *MemoryUploadCache) Set(_ context.Context, key, value string, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry := memoryCacheEntry{value: value}
+	if ttl > 0 {
+		entry.expiresAt = c.now().Add(ttl)
+	}
+	c.entries[key] = entry
+}
+
+func (c *MemoryUploadCache) Delete(_ context.Context, key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	delete(c.entries, key)
+}
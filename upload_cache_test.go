@@ -0,0 +1,156 @@
+package maxbot
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+func TestMemoryUploadCache(t *testing.T) {
+	ctx := context.Background()
+	now := time.Unix(0, 0)
+	cache := NewMemoryUploadCache()
+	cache.now = func() time.Time { return now }
+
+	cache.Set(ctx, "ttl", "a", time.Minute)
+	cache.Set(ctx, "forever", "b", 0)
+
+	value, ok := cache.Get(ctx, "ttl")
+	assert.True(t, ok)
+	assert.Equal(t, "a", value)
+
+	now = now.Add(time.Minute)
+	_, ok = cache.Get(ctx, "ttl")
+	assert.False(t, ok)
+
+	value, ok = cache.Get(ctx, "forever")
+	assert.True(t, ok)
+	assert.Equal(t, "b", value)
+
+	cache.Delete(ctx, "forever")
+	_, ok = cache.Get(ctx, "forever")
+	assert.False(t, ok)
+}
+
+func TestError_IsAttachmentError(t *testing.T) {
+	assert.True(t, Error{Code: "attachment.invalid"}.IsAttachmentError())
+	assert.False(t, Error{Code: "attachment.not.ready"}.IsAttachmentError())
+	assert.False(t, Error{Code: "not.found"}.IsAttachmentError())
+}
+
+// newCachingServer считает загрузки файлов и отклоняет сообщения с токеном rejectToken.
+func newCachingServer(uploads *int32, rejectToken string) *httptest.Server {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case pathUpload:
+			_, _ = w.Write([]byte(`{"url":"` + srv.URL + `/upload-target"}`))
+		case "/upload-target":
+			_, _ = io.Copy(io.Discard, r.Body)
+			token := "token_" + strconv.Itoa(int(atomic.AddInt32(uploads, 1)))
+			_, _ = w.Write([]byte(`{"token":"` + token + `","photos":{"p":{"token":"` + token + `"}}}`))
+		case pathMessages:
+			body, _ := io.ReadAll(r.Body)
+			if rejectToken != "" && bytes.Contains(body, []byte(rejectToken)) {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"code":"attachment.invalid","message":"invalid token"}`))
+
+				return
+			}
+			_, _ = w.Write([]byte(`{"message":{}}`))
+		}
+	}))
+
+	return srv
+}
+
+func TestUpload_Cache(t *testing.T) {
+	var uploads int32
+	srv := newCachingServer(&uploads, "")
+	defer srv.Close()
+
+	api, err := NewApi(testToken, WithBaseURL(srv.URL), WithUploadCache(NewMemoryUploadCache(), time.Hour))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	data := []byte("brochure")
+
+	first, err := api.ExtendedUpload().UploadBytes(ctx, model.UploadFile, "a.pdf", data)
+	require.NoError(t, err)
+	second, err := api.ExtendedUpload().UploadBytes(ctx, model.UploadFile, "b.pdf", data)
+	require.NoError(t, err)
+
+	assert.Equal(t, first, second)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&uploads))
+
+	// другой тип загрузки - другой ключ
+	_, err = api.ExtendedUpload().UploadBytes(ctx, model.UploadAudio, "a.pdf", data)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&uploads))
+
+	// источник без io.Seeker не кешируется
+	_, err = api.Upload.Upload(ctx, model.UploadFile, io.MultiReader(bytes.NewReader(data)), "a.pdf", int64(len(data)))
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&uploads))
+
+	// загрузка частями использует тот же кеш
+	resumable, err := api.ExtendedUpload().UploadResumable(ctx, model.UploadFile, strings.NewReader("brochure"), "a.pdf", int64(len(data)))
+	require.NoError(t, err)
+	assert.Equal(t, first, resumable)
+	assert.Equal(t, int32(3), atomic.LoadInt32(&uploads))
+}
+
+func TestUpload_CacheKeepsReaderPosition(t *testing.T) {
+	var uploads int32
+	srv := newCachingServer(&uploads, "")
+	defer srv.Close()
+
+	api, err := NewApi(testToken, WithBaseURL(srv.URL), WithUploadCache(NewMemoryUploadCache(), 0))
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		token, at, err := api.ExtendedUpload().UploadAuto(context.Background(), bytes.NewReader(pngHeader), "logo.bin", int64(len(pngHeader)))
+		require.NoError(t, err)
+		assert.Equal(t, "token_1", token)
+		assert.Equal(t, model.AttachImage, at)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&uploads))
+}
+
+func TestMessages_SendInvalidatesUploadCache(t *testing.T) {
+	var uploads int32
+	srv := newCachingServer(&uploads, "token_1")
+	defer srv.Close()
+
+	api, err := NewApi(testToken, WithBaseURL(srv.URL), WithUploadCache(NewMemoryUploadCache(), time.Hour))
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	data := []byte("logo")
+
+	token, err := api.ExtendedUpload().UploadBytes(ctx, model.UploadFile, "logo.txt", data)
+	require.NoError(t, err)
+	require.Equal(t, "token_1", token)
+
+	_, err = api.Messages.Send(ctx, NewMessage().SetChat(1).AddAttachByToken(token, model.AttachFile))
+	require.Error(t, err)
+
+	token, err = api.ExtendedUpload().UploadBytes(ctx, model.UploadFile, "logo.txt", data)
+	require.NoError(t, err)
+	assert.Equal(t, "token_2", token)
+
+	_, err = api.Messages.Send(ctx, NewMessage().SetChat(1).AddAttachByToken(token, model.AttachFile))
+	require.NoError(t, err)
+}
@@ -0,0 +1,127 @@
+package maxbot
+
+import (
+	"io"
+	"time"
+)
+
+type UploadOpt func(o *uploadOptions)
+
+type uploadOptions struct {
+	progress         func(UploadProgress)
+	progressInterval time.Duration
+	chunkSize        int64
+	chunkAttempts    int
+	chunkRetryPause  time.Duration
+}
+
+// UploadProgress состояние загрузки файла.
+type UploadProgress struct {
+	// Sent количество отправленных байт файла.
+	Sent int64
+	// Total размер файла.
+	Total int64
+	// Rate средняя скорость загрузки, байт в секунду.
+	Rate float64
+}
+
+// Percent возвращает процент загрузки от 0 до 100.
+func (p UploadProgress) Percent() float64 {
+	if p.Total <= 0 {
+		return 0
+	}
+
+	return float64(p.Sent) * 100 / float64(p.Total)
+}
+
+// WithUploadProgress вызывает fn по мере отправки файла, но не чаще одного раза в interval.
+// После отправки последнего байта fn вызывается всегда.
+func WithUploadProgress(fn func(UploadProgress), interval time.Duration) UploadOpt {
+	return func(o *uploadOptions) {
+		o.progress = fn
+		o.progressInterval = interval
+	}
+}
+
+// WithChunkSize задаёт размер части файла для UploadResumable.
+func WithChunkSize(size int64) UploadOpt {
+	return func(o *uploadOptions) {
+		if size > 0 {
+			o.chunkSize = size
+		}
+	}
+}
+
+// WithChunkRetry задаёт количество попыток отправки каждой части файла для UploadResumable
+// и паузу перед первой повторной попыткой. Пауза удваивается с каждой попыткой.
+func WithChunkRetry(attempts int, pause time.Duration) UploadOpt {
+	return func(o *uploadOptions) {
+		if attempts > 0 {
+			o.chunkAttempts = attempts
+		}
+		o.chunkRetryPause = pause
+	}
+}
+
+func newUploadOptions(opts []UploadOpt) uploadOptions {
+	o := uploadOptions{
+		chunkSize:       defaultChunkSize,
+		chunkAttempts:   maxRetries,
+		chunkRetryPause: time.Second,
+	}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return o
+}
+
+type progressReader struct {
+	r        io.Reader
+	fn       func(UploadProgress)
+	interval time.Duration
+	total    int64
+	sent     int64
+	start    time.Time
+	last     time.Time
+}
+
+func newProgressReader(r io.Reader, total int64, o uploadOptions) io.Reader {
+	if o.progress == nil {
+		return r
+	}
+
+	return &progressReader{
+		r:        r,
+		fn:       o.progress,
+		interval: o.progressInterval,
+		total:    total,
+		start:    time.Now(),
+	}
+}
+
+func (p *progressReader) Read(b []byte) (int, error) {
+	n, err := p.r.Read(b)
+	p.sent += int64(n)
+
+	now := time.Now()
+	done := err == io.EOF || (p.total > 0 && p.sent >= p.total)
+	if n > 0 && (done || now.Sub(p.last) >= p.interval) {
+		p.last = now
+		p.report(now)
+	}
+
+	return n, err
+}
+
+func (p *progressReader) report(now time.Time) {
+	progress := UploadProgress{
+		Sent:  p.sent,
+		Total: p.total,
+	}
+	if elapsed := now.Sub(p.start).Seconds(); elapsed > 0 {
+		progress.Rate = float64(p.sent) / elapsed
+	}
+
+	p.fn(progress)
+}
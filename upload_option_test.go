@@ -0,0 +1,100 @@
+package maxbot
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+func TestUploadProgress_Percent(t *testing.T) {
+	assert.Equal(t, float64(50), UploadProgress{Sent: 5, Total: 10}.Percent())
+	assert.Equal(t, float64(0), UploadProgress{Sent: 5}.Percent())
+}
+
+func TestUpload_Progress(t *testing.T) {
+	received := map[string]string{}
+	srv := newUploadServer(t, received)
+	defer srv.Close()
+
+	api, err := NewApi(testToken, WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	data := bytes.Repeat([]byte("a"), 256*1024)
+	var reports []UploadProgress
+	progress := func(p UploadProgress) {
+		reports = append(reports, p)
+	}
+
+	_, err = api.ExtendedUpload().UploadBytes(context.Background(), model.UploadFile, "big.bin", data, WithUploadProgress(progress, 0))
+	require.NoError(t, err)
+
+	require.NotEmpty(t, reports)
+	for i := 1; i < len(reports); i++ {
+		assert.Greater(t, reports[i].Sent, reports[i-1].Sent)
+	}
+
+	last := reports[len(reports)-1]
+	assert.Equal(t, int64(len(data)), last.Sent)
+	assert.Equal(t, int64(len(data)), last.Total)
+	assert.Equal(t, float64(100), last.Percent())
+}
+
+func TestUpload_ProgressInterval(t *testing.T) {
+	var reports []UploadProgress
+	o := newUploadOptions([]UploadOpt{WithUploadProgress(func(p UploadProgress) {
+		reports = append(reports, p)
+	}, time.Hour)})
+
+	r := newProgressReader(strings.NewReader(strings.Repeat("a", 100)), 100, o)
+	buf := make([]byte, 10)
+	for {
+		if _, err := r.Read(buf); err != nil {
+			break
+		}
+	}
+
+	// первый и последний вызов: остальные подавлены интервалом
+	require.Len(t, reports, 2)
+	assert.Equal(t, int64(10), reports[0].Sent)
+	assert.Equal(t, int64(100), reports[1].Sent)
+}
+
+func TestUpload_CancelDuringUpload(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cli := newClient(testToken, "api.example.com")
+	cli.httpClient = &mockHttpClient{
+		doFunc: func(req *http.Request) (*http.Response, error) {
+			if req.URL.Path == pathUpload {
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Body:       io.NopCloser(strings.NewReader(`{"url":"https://upload.example.com/upload"}`)),
+				}, nil
+			}
+
+			// читаем начало файла и прерываем загрузку, не дочитав тело запроса
+			_, _ = io.ReadFull(req.Body, make([]byte, 1024))
+			cancel()
+			<-req.Context().Done()
+
+			return nil, req.Context().Err()
+		},
+	}
+
+	data := bytes.Repeat([]byte("a"), 1024*1024)
+	_, err := newUpload(cli).Upload(ctx, model.UploadFile, bytes.NewReader(data), "big.bin", int64(len(data)))
+
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, context.Canceled))
+}
@@ -0,0 +1,231 @@
+package maxbot
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+// UploadResumable загружает файл частями с заголовком Content-Range. Неудачная отправка части
+// повторяется без перезапуска всей загрузки. src должен реализовывать io.ReaderAt или io.ReadSeeker,
+// чтобы часть можно было прочитать повторно.
+func (u *Upload) UploadResumable(ctx context.Context, uploadType model.UploadType, src io.Reader, name string, size int64, opts ...UploadOpt) (token string, err error) {
+	readerAt, err := newChunkSource(src)
+	if err != nil {
+		return
+	}
+	if size <= 0 {
+		err = fmt.Errorf("upload: resumable upload requires positive size, got %d", size)
+
+		return
+	}
+
+	cache := u.client.uploadCache
+	if cache == nil {
+		res, rErr := u.uploadResumable(ctx, uploadType, readerAt, name, size, opts...)
+
+		return res.Token, rErr
+	}
+
+	key, err := uploadCacheKey(uploadType, io.NewSectionReader(readerAt, 0, size))
+	if err != nil {
+		return
+	}
+
+	if cached, found := cache.get(ctx, key); found {
+		return cached.Token, nil
+	}
+
+	res, err := u.uploadResumable(ctx, uploadType, readerAt, name, size, opts...)
+	if err == nil {
+		cache.set(ctx, key, res)
+	}
+
+	return res.Token, err
+}
+
+func (u *Upload) uploadResumable(ctx context.Context, uploadType model.UploadType, readerAt io.ReaderAt, name string, size int64, opts ...UploadOpt) (res model.UploadResult, err error) {
+	defer func() { u.client.onUpload(ctx, uploadType, size, err) }()
+
+	o := newUploadOptions(opts)
+	name = multipartFileName(name)
+
+	head := make([]byte, min(int64(sniffLen), size))
+	n, err := readerAt.ReadAt(head, 0)
+	if err != nil && !errors.Is(err, io.EOF) {
+		err = fmt.Errorf("read file: %w", err)
+
+		return
+	}
+	contentType := detectContentType(name, head[:n])
+
+	endpoint, err := u.getUploadURL(ctx, uploadType)
+	if err != nil {
+		return
+	}
+
+	chunk := &chunkUpload{
+		upload:      u,
+		endpoint:    endpoint.Url,
+		src:         readerAt,
+		name:        name,
+		contentType: contentType,
+		size:        size,
+		opts:        o,
+	}
+
+	start := time.Now()
+	for offset := int64(0); ; offset += o.chunkSize {
+		end := min(offset+o.chunkSize, size) - 1
+
+		var body io.ReadCloser
+		body, err = chunk.sendWithRetry(ctx, offset, end)
+		if err != nil {
+			return
+		}
+
+		if o.progress != nil {
+			progress := UploadProgress{Sent: end + 1, Total: size}
+			if elapsed := time.Since(start).Seconds(); elapsed > 0 {
+				progress.Rate = float64(end+1) / elapsed
+			}
+			o.progress(progress)
+		}
+
+		// ответ на последнюю часть содержит результат загрузки
+		if end+1 == size {
+			defer func() { _ = body.Close() }()
+
+			return parseUploadResponse(uploadType, endpoint, body)
+		}
+		_ = body.Close()
+	}
+}
+
+type chunkUpload struct {
+	upload      *Upload
+	endpoint    string
+	src         io.ReaderAt
+	name        string
+	contentType string
+	size        int64
+	opts        uploadOptions
+}
+
+func (c *chunkUpload) sendWithRetry(ctx context.Context, start, end int64) (body io.ReadCloser, err error) {
+	pause := c.opts.chunkRetryPause
+	for attempt := 0; attempt < c.opts.chunkAttempts; attempt++ {
+		body, err = c.send(ctx, start, end)
+		if err == nil {
+			return
+		}
+
+		if ctx.Err() != nil || !isChunkRetryable(err) {
+			return
+		}
+
+		if attempt < c.opts.chunkAttempts-1 {
+			select {
+			case <-ctx.Done():
+				err = ctx.Err()
+
+				return
+			case <-time.After(pause):
+			}
+			pause *= 2
+		}
+	}
+
+	err = fmt.Errorf("upload chunk %d-%d failed after %d attempts: %w", start, end, c.opts.chunkAttempts, err)
+
+	return
+}
+
+func (c *chunkUpload) send(ctx context.Context, start, end int64) (io.ReadCloser, error) {
+	section := io.NewSectionReader(c.src, start, end-start+1)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint, section)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.ContentLength = section.Size()
+	req.Header.Set("Content-Type", c.contentType)
+	req.Header.Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": c.name}))
+	req.Header.Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", start, end, c.size))
+
+	resp, err := c.upload.client.do(req)
+	if err != nil {
+		return nil, &NetworkError{Op: fmt.Sprintf("upload chunk %d-%d", start, end), Err: err}
+	}
+
+	if c.upload.client.isNotOk(resp.StatusCode) {
+		defer func() { _ = resp.Body.Close() }()
+
+		return nil, &chunkError{statusCode: resp.StatusCode, err: parseResponseError(resp)}
+	}
+
+	return resp.Body, nil
+}
+
+type chunkError struct {
+	statusCode int
+	err        error
+}
+
+func (e *chunkError) Error() string {
+	return fmt.Sprintf("upload chunk: status %d: %v", e.statusCode, e.err)
+}
+
+func (e *chunkError) Unwrap() error {
+	return e.err
+}
+
+// isChunkRetryable повторяет сетевые ошибки, ответы 5xx и 429. Остальные ответы 4xx считаются окончательными.
+func isChunkRetryable(err error) bool {
+	var chunkErr *chunkError
+	if errors.As(err, &chunkErr) {
+		return chunkErr.statusCode >= http.StatusInternalServerError || chunkErr.statusCode == http.StatusTooManyRequests
+	}
+
+	return true
+}
+
+func newChunkSource(src io.Reader) (io.ReaderAt, error) {
+	switch r := src.(type) {
+	case io.ReaderAt:
+		return r, nil
+	case io.ReadSeeker:
+		return &readSeekerAt{rs: r}, nil
+	}
+
+	return nil, fmt.Errorf("upload: resumable upload requires io.ReaderAt or io.ReadSeeker, got %T", src)
+}
+
+// readSeekerAt реализует io.ReaderAt поверх io.ReadSeeker.
+type readSeekerAt struct {
+	mu sync.Mutex
+	rs io.ReadSeeker
+}
+
+func (r *readSeekerAt) ReadAt(p []byte, off int64) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, err := r.rs.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	n, err := io.ReadFull(r.rs, p)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		err = io.EOF
+	}
+
+	return n, err
+}
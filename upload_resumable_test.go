@@ -0,0 +1,173 @@
+package maxbot
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+// chunkServer эмулирует приём файла частями с заголовком Content-Range.
+type chunkServer struct {
+	mu       sync.Mutex
+	data     []byte
+	ranges   []string
+	failures map[string]int
+	status   int
+}
+
+func (s *chunkServer) handler(t *testing.T, srvURL func() string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == pathUpload {
+			_, _ = w.Write([]byte(`{"url":"` + srvURL() + `/chunk","token":"media_token"}`))
+
+			return
+		}
+
+		s.mu.Lock()
+		defer s.mu.Unlock()
+
+		contentRange := r.Header.Get("Content-Range")
+		s.ranges = append(s.ranges, contentRange)
+
+		if s.failures[contentRange] > 0 {
+			s.failures[contentRange]--
+			_, _ = io.Copy(io.Discard, r.Body)
+			w.WriteHeader(s.status)
+			_, _ = w.Write([]byte(`{"code":"chunk.failed","message":"failed"}`))
+
+			return
+		}
+
+		var start, end, total int64
+		_, err := fmt.Sscanf(contentRange, "bytes %d-%d/%d", &start, &end, &total)
+		require.NoError(t, err)
+		assert.Equal(t, `attachment; filename=video.mp4`, r.Header.Get("Content-Disposition"))
+
+		body, _ := io.ReadAll(r.Body)
+		assert.Equal(t, end-start+1, int64(len(body)))
+		if int64(len(s.data)) < total {
+			s.data = append(s.data, make([]byte, total-int64(len(s.data)))...)
+		}
+		copy(s.data[start:], body)
+
+		if end+1 < total {
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprintf(w, "%d-%d/%d", start, end, total)
+
+			return
+		}
+
+		_, _ = w.Write([]byte(`{"token":"file_token"}`))
+	}
+}
+
+func newChunkTestServer(t *testing.T, s *chunkServer) (*httptest.Server, *Api) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(s.handler(t, func() string { return srv.URL }))
+
+	api, err := NewApi(testToken, WithBaseURL(srv.URL))
+	require.NoError(t, err)
+
+	return srv, api
+}
+
+func TestUpload_UploadResumable(t *testing.T) {
+	s := &chunkServer{
+		failures: map[string]int{"bytes 10-19/25": 2},
+		status:   http.StatusServiceUnavailable,
+	}
+	srv, api := newChunkTestServer(t, s)
+	defer srv.Close()
+
+	content := strings.Repeat("0123456789", 2) + "abcde"
+
+	var reports []UploadProgress
+	token, err := api.ExtendedUpload().UploadResumable(context.Background(), model.UploadFile, strings.NewReader(content), "video.mp4", int64(len(content)),
+		WithChunkSize(10),
+		WithChunkRetry(3, time.Millisecond),
+		WithUploadProgress(func(p UploadProgress) { reports = append(reports, p) }, 0),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, "file_token", token)
+	assert.Equal(t, content, string(s.data))
+	assert.Equal(t, []string{
+		"bytes 0-9/25",
+		"bytes 10-19/25",
+		"bytes 10-19/25",
+		"bytes 10-19/25",
+		"bytes 20-24/25",
+	}, s.ranges)
+
+	require.Len(t, reports, 3)
+	assert.Equal(t, int64(25), reports[2].Sent)
+}
+
+func TestUpload_UploadResumable_VideoToken(t *testing.T) {
+	s := &chunkServer{}
+	srv, api := newChunkTestServer(t, s)
+	defer srv.Close()
+
+	content := bytes.Repeat([]byte{1}, 30)
+	// io.ReadSeeker без io.ReaderAt
+	src := struct{ io.ReadSeeker }{bytes.NewReader(content)}
+
+	token, err := api.ExtendedUpload().UploadResumable(context.Background(), model.UploadVideo, src, "video.mp4", int64(len(content)), WithChunkSize(7))
+	require.NoError(t, err)
+
+	assert.Equal(t, "media_token", token)
+	assert.Equal(t, content, s.data)
+	assert.Len(t, s.ranges, 5)
+}
+
+func TestUpload_UploadResumable_Errors(t *testing.T) {
+	t.Run("client error is not retried", func(t *testing.T) {
+		s := &chunkServer{
+			failures: map[string]int{"bytes 0-9/10": 5},
+			status:   http.StatusBadRequest,
+		}
+		srv, api := newChunkTestServer(t, s)
+		defer srv.Close()
+
+		_, err := api.ExtendedUpload().UploadResumable(context.Background(), model.UploadFile, strings.NewReader("0123456789"), "video.mp4", 10,
+			WithChunkRetry(3, time.Millisecond))
+		require.Error(t, err)
+		assert.Len(t, s.ranges, 1)
+	})
+
+	t.Run("attempts exhausted", func(t *testing.T) {
+		s := &chunkServer{
+			failures: map[string]int{"bytes 0-9/10": 5},
+			status:   http.StatusBadGateway,
+		}
+		srv, api := newChunkTestServer(t, s)
+		defer srv.Close()
+
+		_, err := api.ExtendedUpload().UploadResumable(context.Background(), model.UploadFile, strings.NewReader("0123456789"), "video.mp4", 10,
+			WithChunkRetry(2, time.Millisecond))
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "failed after 2 attempts")
+		assert.Len(t, s.ranges, 2)
+	})
+
+	t.Run("not seekable source", func(t *testing.T) {
+		api, err := NewApi(testToken)
+		require.NoError(t, err)
+
+		_, err = api.ExtendedUpload().UploadResumable(context.Background(), model.UploadFile, io.MultiReader(strings.NewReader("data")), "video.mp4", 4)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "io.ReaderAt or io.ReadSeeker")
+	})
+}
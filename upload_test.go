@@ -5,6 +5,9 @@ import (
 	"context"
 	"io"
 	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -428,7 +431,7 @@ func TestMultipartEnvelope(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			contentType, contentLength, boundary, err := multipartEnvelope(tt.fileName, tt.fileSize)
+			contentType, contentLength, boundary, err := multipartEnvelope(tt.fileName, defaultContentType, tt.fileSize)
 
 			if err != nil {
 				t.Errorf("Unexpected error: %v", err)
@@ -448,3 +451,190 @@ func TestMultipartEnvelope(t *testing.T) {
 		})
 	}
 }
+
+// newUploadServer эмулирует получение ссылки для загрузки и приём файла.
+// В received сохраняются имя и содержимое загруженного файла.
+func newUploadServer(t *testing.T, received map[string]string) *httptest.Server {
+	t.Helper()
+
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case pathUpload:
+			_, _ = w.Write([]byte(`{"url":"` + srv.URL + `/upload-target"}`))
+		case "/upload-target":
+			file, header, err := r.FormFile(fieldData)
+			if err != nil {
+				t.Errorf("parse multipart: %v", err)
+				w.WriteHeader(http.StatusBadRequest)
+
+				return
+			}
+			data, _ := io.ReadAll(file)
+			received[header.Filename] = string(data)
+			_, _ = w.Write([]byte(`{"token":"file_token"}`))
+		case "/files/report.pdf":
+			_, _ = w.Write([]byte("pdf content"))
+		case "/download":
+			w.Header().Set("Content-Disposition", `attachment; filename="named.txt"`)
+			w.WriteHeader(http.StatusOK)
+			// без Content-Length: ответ передаётся частями
+			w.(http.Flusher).Flush()
+			_, _ = w.Write([]byte("streamed content"))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+
+	return srv
+}
+
+func TestUpload_UploadFile(t *testing.T) {
+	received := map[string]string{}
+	srv := newUploadServer(t, received)
+	defer srv.Close()
+
+	filePath := filepath.Join(t.TempDir(), "doc.txt")
+	if err := os.WriteFile(filePath, []byte("file on disk"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	api, err := NewApi(testToken, WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := api.ExtendedUpload().UploadFile(context.Background(), model.UploadFile, filePath)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if token != "file_token" {
+		t.Errorf("Expected token 'file_token', got '%s'", token)
+	}
+	if received["doc.txt"] != "file on disk" {
+		t.Errorf("Unexpected uploaded content: %v", received)
+	}
+
+	_, err = api.ExtendedUpload().UploadFile(context.Background(), model.UploadFile, filepath.Join(t.TempDir(), "missing"))
+	if err == nil || !strings.Contains(err.Error(), "open file") {
+		t.Errorf("Expected open file error, got %v", err)
+	}
+}
+
+func TestUpload_UploadBytes(t *testing.T) {
+	received := map[string]string{}
+	srv := newUploadServer(t, received)
+	defer srv.Close()
+
+	api, err := NewApi(testToken, WithBaseURL(srv.URL))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = api.ExtendedUpload().UploadBytes(context.Background(), model.UploadFile, "data.bin", []byte{1, 2, 3})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if received["data.bin"] != "\x01\x02\x03" {
+		t.Errorf("Unexpected uploaded content: %v", received)
+	}
+}
+
+func TestUpload_UploadFromURL(t *testing.T) {
+	tests := []struct {
+		name         string
+		path         string
+		expectedName string
+		expectedData string
+		expectError  bool
+	}{
+		{"known size, name from path", "/files/report.pdf", "report.pdf", "pdf content", false},
+		{"unknown size, name from header", "/download", "named.txt", "streamed content", false},
+		{"not found", "/missing", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			received := map[string]string{}
+			srv := newUploadServer(t, received)
+			defer srv.Close()
+
+			api, err := NewApi(testToken, WithBaseURL(srv.URL))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			_, err = api.ExtendedUpload().UploadFromURL(context.Background(), model.UploadFile, srv.URL+tt.path)
+			if tt.expectError {
+				if err == nil {
+					t.Errorf("Expected error but got none")
+				}
+
+				return
+			}
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if received[tt.expectedName] != tt.expectedData {
+				t.Errorf("Unexpected uploaded files: %v", received)
+			}
+		})
+	}
+}
+
+func TestUpload_UploadDetailed(t *testing.T) {
+	var srv *httptest.Server
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case pathUpload:
+			_, _ = w.Write([]byte(`{"url":"` + srv.URL + `/upload-target","token":"endpoint_token"}`))
+		case "/upload-target":
+			_, _ = w.Write([]byte(`{"photos":{"b":{"token":"token_b"},"a":{"token":"token_a"}}}`))
+		}
+	}))
+	defer srv.Close()
+
+	api, err := NewApi(testToken, WithBaseURL(srv.URL), WithUploadCache(NewMemoryUploadCache(), 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 2; i++ {
+		res, err := api.ExtendedUpload().UploadDetailed(context.Background(), model.UploadImage, bytes.NewReader(pngHeader), "a.png", int64(len(pngHeader)))
+		if err != nil {
+			t.Fatalf("Unexpected error: %v", err)
+		}
+		if res.Type != model.UploadImage || res.Token != "token_a" || res.EndpointToken != "endpoint_token" {
+			t.Errorf("Unexpected result: %+v", res)
+		}
+		if len(res.Photos) != 2 || res.Photos["b"].Token != "token_b" {
+			t.Errorf("Expected all photo tokens, got %v", res.Photos)
+		}
+		if !strings.Contains(string(res.Response), `"token_b"`) {
+			t.Errorf("Expected raw response, got %s", res.Response)
+		}
+	}
+}
+
+type stubUpload struct{}
+
+func (stubUpload) Upload(context.Context, model.UploadType, io.Reader, string, int64) (string, error) {
+	return "stub", nil
+}
+
+func TestApi_ExtendedUpload(t *testing.T) {
+	api, err := NewApi(testToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if api.ExtendedUpload() != api.Upload {
+		t.Error("Expected ExtendedUpload to return Upload")
+	}
+
+	// UploadAPI без дополнительных методов можно присвоить полю Upload
+	api.Upload = stubUpload{}
+	if api.ExtendedUpload() != api.upload {
+		t.Error("Expected ExtendedUpload to fall back to the default uploader")
+	}
+}
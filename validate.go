@@ -0,0 +1,178 @@
+package maxbot
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+// Ограничения клавиатуры.
+const (
+	MaxKeyboardRows         = 30
+	MaxKeyboardButtons      = 210
+	MaxButtonsPerRow        = 7
+	MaxSpecialButtonsPerRow = 3
+	MaxButtonTextLength     = 128
+	MaxButtonPayloadLength  = 1024
+	MaxButtonURLLength      = 2048
+)
+
+// ValidationError ошибка в поле сообщения. Field - путь к полю в теле запроса,
+// например "attachments[1].payload.buttons[0][2].text".
+type ValidationError struct {
+	Field  string
+	Reason string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Field + ": " + e.Reason
+}
+
+// ValidationErrors все ошибки, найденные Message.Validate.
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+
+	return "invalid message: " + strings.Join(msgs, "; ")
+}
+
+// WithValidation включает проверку сообщения Message.Validate перед отправкой в Messages.Send.
+func WithValidation() Opt {
+	return func(c *client) error {
+		c.validateMessages = true
+
+		return nil
+	}
+}
+
+// Validate проверяет сообщение до отправки: получателя, длину текста, сочетание вложений
+// и размер клавиатуры. Возвращает ValidationErrors или nil.
+func (m *Message) Validate() error {
+	var errs ValidationErrors
+	add := func(field, format string, args ...any) {
+		errs = append(errs, &ValidationError{Field: field, Reason: fmt.Sprintf(format, args...)})
+	}
+
+	switch {
+	case m.userID == 0 && m.chatID == 0:
+		add("recipient", "user or chat must be set")
+	case m.userID != 0 && m.chatID != 0:
+		add("recipient", "only one of user or chat must be set")
+	}
+
+	errs = append(errs, m.validateBody()...)
+	if len(errs) > 0 {
+		return errs
+	}
+
+	return nil
+}
+
+// validateBody проверяет тело сообщения без получателя.
+func (m *Message) validateBody() (errs ValidationErrors) {
+	add := func(field, format string, args ...any) {
+		errs = append(errs, &ValidationError{Field: field, Reason: fmt.Sprintf(format, args...)})
+	}
+
+	if n := utf8.RuneCountInString(m.message.Text); n > MaxTextLength {
+		add("text", "length %d exceeds %d characters", n, MaxTextLength)
+	}
+
+	var keyboards int
+	for i, attach := range m.message.Attachments {
+		field := fmt.Sprintf("attachments[%d]", i)
+
+		switch attach.Type {
+		case model.AttachSticker, model.AttachContact:
+			if len(m.message.Attachments) > 1 || m.message.Text != "" {
+				add(field, "%s must be the only attachment in a message without text", attach.Type)
+			}
+		case model.AttachInlineKeyboard:
+			keyboards++
+			if keyboards > 1 {
+				add(field, "message can contain only one keyboard")
+			}
+			for _, err := range validateKeyboard(attach.Payload.Buttons) {
+				err.Field = field + ".payload." + err.Field
+				errs = append(errs, err)
+			}
+		}
+	}
+
+	return errs
+}
+
+func validateKeyboard(rows [][]*model.Button) (errs ValidationErrors) {
+	add := func(field, format string, args ...any) {
+		errs = append(errs, &ValidationError{Field: field, Reason: fmt.Sprintf(format, args...)})
+	}
+
+	if len(rows) == 0 {
+		add("buttons", "keyboard must contain at least one row")
+	}
+	if len(rows) > MaxKeyboardRows {
+		add("buttons", "%d rows exceed %d", len(rows), MaxKeyboardRows)
+	}
+
+	var total int
+	for i, row := range rows {
+		field := fmt.Sprintf("buttons[%d]", i)
+		total += len(row)
+
+		limit := MaxButtonsPerRow
+		for _, btn := range row {
+			if btn != nil && isSpecialButton(btn.Type) {
+				limit = MaxSpecialButtonsPerRow
+
+				break
+			}
+		}
+		if len(row) == 0 {
+			add(field, "row must contain at least one button")
+		}
+		if len(row) > limit {
+			add(field, "%d buttons exceed %d per row", len(row), limit)
+		}
+
+		for j, btn := range row {
+			btnField := fmt.Sprintf("%s[%d]", field, j)
+			if btn == nil {
+				add(btnField, "button is nil")
+
+				continue
+			}
+
+			if n := utf8.RuneCountInString(btn.Text); n == 0 || n > MaxButtonTextLength {
+				add(btnField+".text", "length %d must be from 1 to %d characters", n, MaxButtonTextLength)
+			}
+			if n := utf8.RuneCountInString(btn.Payload); n > MaxButtonPayloadLength {
+				add(btnField+".payload", "length %d exceeds %d characters", n, MaxButtonPayloadLength)
+			}
+			if n := utf8.RuneCountInString(btn.URL); n > MaxButtonURLLength {
+				add(btnField+".url", "length %d exceeds %d characters", n, MaxButtonURLLength)
+			}
+		}
+	}
+
+	if total > MaxKeyboardButtons {
+		add("buttons", "%d buttons exceed %d", total, MaxKeyboardButtons)
+	}
+
+	return
+}
+
+// isSpecialButton кнопки, которых в строке может быть не больше MaxSpecialButtonsPerRow.
+func isSpecialButton(t model.ButtonType) bool {
+	switch t {
+	case model.ButtonLink, model.ButtonOpenApp, model.ButtonRequestGeo, model.ButtonRequestContact:
+		return true
+	}
+
+	return false
+}
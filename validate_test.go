@@ -0,0 +1,100 @@
+package maxbot
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/max-messenger/max-bot-api-client-go/v2/model"
+)
+
+func TestMessage_Validate(t *testing.T) {
+	tooWide := model.NewKeyboard()
+	row := tooWide.AddRow()
+	for i := 0; i < MaxButtonsPerRow+1; i++ {
+		row.AddCallBack("b", "p")
+	}
+
+	links := model.NewKeyboard()
+	links.AddRow().AddLink("1", "https://max.ru").AddLink("2", "https://max.ru").
+		AddLink("3", "https://max.ru").AddLink("4", "https://max.ru")
+
+	badButtons := model.NewKeyboard()
+	badButtons.AddRow().AddCallBack("", strings.Repeat("p", MaxButtonPayloadLength+1))
+
+	tooTall := model.NewKeyboard()
+	for i := 0; i < MaxKeyboardRows+1; i++ {
+		tooTall.AddRow().AddCallBack("b", "p")
+	}
+
+	valid := model.NewKeyboard()
+	valid.AddRow().AddCallBack("OK", "ok").AddLink("Docs", "https://dev.max.ru")
+
+	tests := []struct {
+		name   string
+		msg    *Message
+		fields []string
+	}{
+		{"valid", NewMessage().SetChat(1).SetText("hi").AddKeyboard(valid), nil},
+		{"no recipient", NewMessage().SetText("hi"), []string{"recipient"}},
+		{"two recipients", NewMessage().SetUser(1).SetChat(2).SetText("hi"), []string{"recipient"}},
+		{"long text", NewMessage().SetChat(1).SetText(strings.Repeat("я", MaxTextLength+1)), []string{"text"}},
+		{"sticker with text", NewMessage().SetChat(1).SetText("hi").AddSticker("s"), []string{"attachments[0]"}},
+		{"contact with attachment", NewMessage().SetChat(1).AddContact(1).AddShare("https://max.ru"), []string{"attachments[0]"}},
+		{"two keyboards", NewMessage().SetChat(1).AddKeyboard(valid).AddKeyboard(valid), []string{"attachments[1]"}},
+		{"too many buttons in row", NewMessage().SetChat(1).AddKeyboard(tooWide), []string{"attachments[0].payload.buttons[0]"}},
+		{"too many links in row", NewMessage().SetChat(1).AddKeyboard(links), []string{"attachments[0].payload.buttons[0]"}},
+		{"too many rows", NewMessage().SetChat(1).AddKeyboard(tooTall), []string{"attachments[0].payload.buttons"}},
+		{
+			"button text and payload",
+			NewMessage().SetChat(1).AddKeyboard(badButtons),
+			[]string{"attachments[0].payload.buttons[0][0].text", "attachments[0].payload.buttons[0][0].payload"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.msg.Validate()
+			if tt.fields == nil {
+				assert.NoError(t, err)
+
+				return
+			}
+
+			var errs ValidationErrors
+			require.True(t, errors.As(err, &errs), "expected ValidationErrors, got %v", err)
+
+			fields := make([]string, 0, len(errs))
+			for _, e := range errs {
+				fields = append(fields, e.Field)
+			}
+			assert.Equal(t, tt.fields, fields)
+		})
+	}
+}
+
+func TestWithValidation(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		_, _ = w.Write([]byte(`{"message":{}}`))
+	}))
+	defer srv.Close()
+
+	api, err := NewApi(testToken, WithBaseURL(srv.URL), WithValidation())
+	require.NoError(t, err)
+
+	_, err = api.Messages.Send(context.Background(), NewMessage().SetText("no recipient"))
+	assert.ErrorContains(t, err, "recipient: user or chat must be set")
+	assert.Equal(t, 0, calls)
+
+	_, err = api.Messages.Send(context.Background(), NewMessage().SetChat(1).SetText("ok"))
+	assert.NoError(t, err)
+	assert.Equal(t, 1, calls)
+}